@@ -0,0 +1,65 @@
+package main
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/nuke87/go_http_server/internal/auth"
+)
+
+// Liest ADMIN_USER_IDS (kommagetrennte User-IDs), deren Access-Tokens Admin-Rechte haben.
+func adminUserIDsFromEnv(raw string) (map[uuid.UUID]bool, error) {
+	ids := map[uuid.UUID]bool{}
+	for _, entry := range strings.Split(raw, ",") {
+		if entry = strings.TrimSpace(entry); entry == "" {
+			continue
+		}
+		id, err := uuid.Parse(entry)
+		if err != nil {
+			return nil, fmt.Errorf("ADMIN_USER_IDS: %q is not a UUID", entry)
+		}
+		ids[id] = true
+	}
+	return ids, nil
+}
+
+// Meldet, ob der Request Admin-Rechte hat: Bearer ADMIN_TOKEN oder ein gültiges
+// Access-Token eines Users aus ADMIN_USER_IDS.
+func (cfg *apiConfig) isAdmin(r *http.Request) bool {
+	if userID := userIDFromContext(r.Context()); userID != uuid.Nil {
+		return cfg.adminUserIDs[userID]
+	}
+	token, err := auth.GetBearerToken(r.Header)
+	if err != nil {
+		return false
+	}
+	if cfg.adminToken != "" && subtle.ConstantTimeCompare([]byte(token), []byte(cfg.adminToken)) == 1 {
+		return true
+	}
+	userID, err := auth.ValidateJWT(token, cfg.jwtSecret, cfg.clock.Now())
+	return err == nil && cfg.adminUserIDs[userID]
+}
+
+// Meldet, ob Admin-Routen Zugangsdaten verlangen. Mit PLATFORM=dev sind sie offen.
+func (cfg *apiConfig) adminAuthRequired() bool {
+	return cfg.platform != "dev"
+}
+
+// Middleware: Lässt Admin-Routen nur mit PLATFORM=dev oder Admin-Rechten durch (siehe isAdmin).
+// Ohne Authorization-Header 401, mit unzureichenden Zugangsdaten 403.
+func (cfg *apiConfig) middlewareAdmin(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !cfg.adminAuthRequired() || cfg.isAdmin(r) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if r.Header.Get("Authorization") == "" {
+			respondWithError(w, http.StatusUnauthorized, "Admin credentials required", nil)
+			return
+		}
+		respondWithError(w, http.StatusForbidden, "Admin access required", nil)
+	})
+}
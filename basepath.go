@@ -0,0 +1,51 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Bringt BASE_PATH in die Form "/prefix" (ohne abschließenden Schrägstrich), leer wenn nicht gesetzt.
+func normalizeBasePath(p string) (string, error) {
+	p = strings.TrimSpace(p)
+	if p == "" || p == "/" {
+		return "", nil
+	}
+	if !strings.HasPrefix(p, "/") {
+		return "", fmt.Errorf("%q must start with /", p)
+	}
+	if strings.ContainsAny(p, "?#") {
+		return "", fmt.Errorf("%q must be a plain path", p)
+	}
+	return strings.TrimSuffix(p, "/"), nil
+}
+
+// Setzt BASE_PATH vor einen servereigenen Pfad. Alle erzeugten URLs
+// (Location-Header, Links) müssen hierüber gebaut werden.
+func (cfg *apiConfig) urlPath(p string) string {
+	return cfg.basePath + p
+}
+
+// Middleware: Entfernt BASE_PATH vom Anfang eingehender Pfade, damit die Mux-Patterns
+// präfixfrei bleiben. Requests ohne Präfix werden unverändert durchgereicht, so dass
+// ein vorgeschalteter Proxy beide Formen weiterleiten kann.
+func middlewareStripBasePath(basePath string, next http.Handler) http.Handler {
+	if basePath == "" {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		p := r.URL.Path
+		if p != basePath && !strings.HasPrefix(p, basePath+"/") {
+			next.ServeHTTP(w, r)
+			return
+		}
+		r2 := r.Clone(r.Context())
+		r2.URL.Path = strings.TrimPrefix(p, basePath)
+		if r2.URL.Path == "" {
+			r2.URL.Path = "/"
+		}
+		r2.URL.RawPath = ""
+		next.ServeHTTP(w, r2)
+	})
+}
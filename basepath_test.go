@@ -0,0 +1,114 @@
+package main
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/nuke87/go_http_server/pkg/chirpy"
+)
+
+func TestNormalizeBasePath(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    string
+		wantErr bool
+	}{
+		{"", "", false},
+		{"/", "", false},
+		{"/chirpy", "/chirpy", false},
+		{" /chirpy/ ", "/chirpy", false},
+		{"/a/b/", "/a/b", false},
+		{"chirpy", "", true},
+		{"/chirpy?x=1", "", true},
+		{"/chirpy#top", "", true},
+	}
+	for _, tt := range tests {
+		got, err := normalizeBasePath(tt.in)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("normalizeBasePath(%q) = %q, %v; want %q, err %v", tt.in, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+// Liest die URL aus einem Link-Header der Form <url>; rel="next".
+func nextLink(t *testing.T, header http.Header) string {
+	t.Helper()
+	link := header.Get("Link")
+	url, ok := strings.CutSuffix(link, `>; rel="next"`)
+	if !ok || !strings.HasPrefix(url, "<") {
+		t.Fatalf("Link = %q, want <url>; rel=\"next\"", link)
+	}
+	return url[1:]
+}
+
+// Mit BASE_PATH tragen alle erzeugten URLs das Präfix, egal ob der Proxy es mitschickt.
+func TestBasePath(t *testing.T) {
+	ts := newTestServer(t, func(cfg *apiConfig) { cfg.basePath = "/chirpy" })
+	const prefix = "http://chirpy.test/chirpy"
+
+	rec := ts.do(t, "POST", "/chirpy/api/users", "", `{"email":"alice@example.com","password":"hunter22"}`)
+	expectStatus(t, rec, http.StatusCreated)
+	alice := decodeResponse[chirpy.User](t, rec)
+	token := ts.token(t, alice.ID)
+
+	var created []chirpy.Chirp
+	for _, body := range []string{"first", "second", "third"} {
+		rec := ts.do(t, "POST", "/chirpy/api/chirps", token, `{"body":"`+body+`"}`)
+		expectStatus(t, rec, http.StatusCreated)
+		chirp := decodeResponse[chirpy.Chirp](t, rec)
+		want := prefix + "/api/chirps/" + chirp.ShortID
+		if got := rec.Header().Get("Location"); got != want || chirp.URL != want {
+			t.Errorf("Location = %q, url = %q; want %q", got, chirp.URL, want)
+		}
+		created = append(created, chirp)
+	}
+
+	// Die Location zeigt auf den Chirp
+	location := strings.TrimPrefix(created[0].URL, "http://chirpy.test")
+	rec = ts.do(t, "GET", location, "", "")
+	expectStatus(t, rec, http.StatusOK)
+	if got := decodeResponse[chirpy.Chirp](t, rec); got.ID != created[0].ID {
+		t.Errorf("GET %s returned chirp %s, want %s", location, got.ID, created[0].ID)
+	}
+
+	// Seiten über den Link-Header durchlaufen, mit und ohne Präfix im Request
+	for _, start := range []string{"/chirpy/api/chirps?limit=1", "/api/chirps?limit=1"} {
+		t.Run(start, func(t *testing.T) {
+			var got []chirpy.Chirp
+			path := start
+			for {
+				rec := ts.do(t, "GET", path, "", "")
+				expectStatus(t, rec, http.StatusOK)
+				page := decodeResponse[chirpy.ChirpPage](t, rec)
+				got = append(got, page.Chirps...)
+				if page.NextCursor == "" {
+					if link := rec.Header().Get("Link"); link != "" {
+						t.Errorf("last page has Link %q", link)
+					}
+					break
+				}
+				if len(got) > len(created) {
+					t.Fatal("no last page")
+				}
+				next := nextLink(t, rec.Header())
+				if !strings.HasPrefix(next, prefix+"/api/chirps?") || !strings.Contains(next, "cursor="+page.NextCursor) {
+					t.Fatalf("next link = %q", next)
+				}
+				path = strings.TrimPrefix(next, "http://chirpy.test")
+			}
+			if len(got) != len(created) {
+				t.Errorf("walked %d chirps, want %d", len(got), len(created))
+			}
+		})
+	}
+
+	// Der Feed verlinkt seine nächste Seite ebenfalls mit Präfix
+	_, bobToken := ts.createUser(t, "bob@example.com")
+	expectStatus(t, ts.do(t, "POST", "/chirpy/api/users/"+alice.ID.String()+"/follow", bobToken, ""), http.StatusNoContent)
+	rec = ts.do(t, "GET", "/chirpy/api/feed?limit=2", bobToken, "")
+	expectStatus(t, rec, http.StatusOK)
+	if next := nextLink(t, rec.Header()); !strings.HasPrefix(next, prefix+"/api/feed?") {
+		t.Errorf("feed next link = %q", next)
+	}
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Standardliste von User-Agent-Teilstrings, an denen Crawler erkannt werden.
+var defaultBotUserAgents = []string{
+	"bot",
+	"crawler",
+	"spider",
+	"slurp",
+	"curl",
+	"wget",
+	"python-requests",
+	"go-http-client",
+	"headlesschrome",
+	"facebookexternalhit",
+}
+
+// botMatcher erkennt Bots anhand von User-Agent-Teilstrings (ohne Groß-/Kleinschreibung).
+// Die Liste wird einmal zu einem regulären Ausdruck kompiliert.
+type botMatcher struct {
+	re *regexp.Regexp
+}
+
+func newBotMatcher(substrings []string) *botMatcher {
+	quoted := make([]string, 0, len(substrings))
+	for _, s := range substrings {
+		s = strings.TrimSpace(s)
+		if s == "" {
+			continue
+		}
+		quoted = append(quoted, regexp.QuoteMeta(s))
+	}
+	if len(quoted) == 0 {
+		return &botMatcher{}
+	}
+	return &botMatcher{re: regexp.MustCompile("(?i)" + strings.Join(quoted, "|"))}
+}
+
+// Liest BOT_USER_AGENTS (kommagetrennt) über lookupEnv, sonst die Standardliste.
+func botMatcherFromEnv(lookupEnv func(string) (string, bool)) *botMatcher {
+	raw, ok := lookupEnv("BOT_USER_AGENTS")
+	if !ok {
+		return newBotMatcher(defaultBotUserAgents)
+	}
+	return newBotMatcher(strings.Split(raw, ","))
+}
+
+func (m *botMatcher) isBot(userAgent string) bool {
+	if m == nil || m.re == nil {
+		return false
+	}
+	return m.re.MatchString(userAgent)
+}
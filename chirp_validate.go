@@ -0,0 +1,45 @@
+package main
+
+import (
+	"net/http"
+	"strings"
+)
+
+const (
+	maxChirpLength = 140
+
+	errCodeInvalidCharacters = "invalid_characters"
+)
+
+// Umgang mit Unicode-Bidi-Steuerzeichen in Chirps (CHIRP_BIDI_CONTROLS)
+const (
+	bidiStrip  = "strip"  // Entfernen (Standard)
+	bidiReject = "reject" // Chirp mit invalid_characters ablehnen
+)
+
+// Prüft und bereinigt einen Chirp-Body vor dem Profanity-Filter (siehe Text.Validate):
+// mehrzeilig, Bidi-Steuerzeichen je nach bidiPolicy, höchstens maxChirpLength Zeichen
+// (Runes, nicht Bytes). Ein Body nur aus Whitespace wird abgelehnt.
+func validateChirpBody(body, bidiPolicy string) (string, *requestError) {
+	text, reqErr := Text(body).Validate(1, maxChirpLength, textPolicy{Multiline: true, Bidi: bidiPolicy})
+	if reqErr != nil {
+		return "", reqErr.forField("body")
+	}
+	if strings.TrimSpace(string(text)) == "" {
+		return "", &requestError{status: http.StatusBadRequest, code: errCodeMissingField, field: "body", msg: "body must not be only whitespace"}
+	}
+	return string(text), nil
+}
+
+// Unicode-Steuerzeichen für bidirektionalen Text (Embeddings, Overrides, Isolates, Marks).
+func isBidiControl(r rune) bool {
+	switch {
+	case r >= 0x202A && r <= 0x202E: // LRE, RLE, PDF, LRO, RLO
+		return true
+	case r >= 0x2066 && r <= 0x2069: // LRI, RLI, FSI, PDI
+		return true
+	case r == 0x200E, r == 0x200F, r == 0x061C: // LRM, RLM, ALM
+		return true
+	}
+	return false
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestValidateChirpBodyLength(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		wantCode string // leer: gültig
+	}{
+		{"ascii at limit", strings.Repeat("a", maxChirpLength), ""},
+		{"ascii over limit", strings.Repeat("a", maxChirpLength+1), errCodeTooLong},
+		{"umlauts at limit", strings.Repeat("ä", maxChirpLength), ""},
+		{"umlauts over limit", strings.Repeat("ü", maxChirpLength+1), errCodeTooLong},
+		{"emoji at limit", strings.Repeat("🐦", maxChirpLength), ""},
+		{"emoji over limit", strings.Repeat("🐦", maxChirpLength+1), errCodeTooLong},
+		{"cjk at limit", strings.Repeat("鳥", maxChirpLength), ""},
+		{"mixed at limit", strings.Repeat("aä🐦鳥", maxChirpLength/4), ""},
+		// e + kombinierender Akut wird per NFC zu einem Zeichen é
+		{"decomposed accents at limit", strings.Repeat("e\u0301", maxChirpLength), ""},
+		{"decomposed accents over limit", strings.Repeat("e\u0301", maxChirpLength+1), errCodeTooLong},
+		{"empty", "", errCodeTooShort},
+		{"only spaces", "     ", errCodeMissingField},
+		{"only newlines and tabs", "\n\t\n \t", errCodeMissingField},
+		{"only non-breaking and ideographic spaces", "\u00a0\u3000", errCodeMissingField},
+		{"only bidi marks", "\u200e\u200f", errCodeTooShort},
+		{"whitespace around text", "  hi  ", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, reqErr := validateChirpBody(tt.body, bidiStrip)
+			if tt.wantCode == "" {
+				if reqErr != nil {
+					t.Fatalf("rejected %d-rune body: %s (%s)", utf8.RuneCountInString(tt.body), reqErr.msg, reqErr.code)
+				}
+				if n := utf8.RuneCountInString(got); n > maxChirpLength {
+					t.Errorf("accepted body has %d runes", n)
+				}
+				return
+			}
+			if reqErr == nil {
+				t.Fatalf("accepted %q", got)
+			}
+			if reqErr.code != tt.wantCode || reqErr.field != "body" {
+				t.Errorf("code/field = %q/%q, want %q/body", reqErr.code, reqErr.field, tt.wantCode)
+			}
+		})
+	}
+}
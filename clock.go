@@ -0,0 +1,26 @@
+package main
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// clock liefert die aktuelle Zeit des Servers. Im Dev-Betrieb lässt sie sich über
+// POST /admin/testing/time-travel verschieben, damit zeitabhängige Tests schnell laufen.
+type clock struct {
+	offset atomic.Int64     // Verschiebung in Nanosekunden
+	now    func() time.Time // Zeitquelle, nil für time.Now; Tests setzen eine feste Zeit
+}
+
+func (c *clock) Now() time.Time {
+	now := time.Now
+	if c.now != nil {
+		now = c.now
+	}
+	return now().Add(time.Duration(c.offset.Load()))
+}
+
+// Verschiebt die Uhr um d und liefert die neue Gesamtverschiebung.
+func (c *clock) Shift(d time.Duration) time.Duration {
+	return time.Duration(c.offset.Add(int64(d)))
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// serverConfig enthält die Einstellungen des HTTP-Servers.
+type serverConfig struct {
+	Port           string        // PORT, Standard 8080
+	FilepathRoot   string        // FILEPATH_ROOT, Verzeichnis für /app/, Standard "."
+	ReadTimeout    time.Duration // READ_TIMEOUT, gilt auch für die Header (gegen Slowloris)
+	WriteTimeout   time.Duration // WRITE_TIMEOUT
+	IdleTimeout    time.Duration // IDLE_TIMEOUT für Keep-Alive-Verbindungen
+	MaxHeaderBytes int           // MAX_HEADER_BYTES
+}
+
+// Liest die Server-Konfiguration über getenv (z.B. os.Getenv). Alle ungültigen
+// Variablen werden gemeinsam gemeldet, jeweils mit Namen und Wert.
+func loadServerConfig(getenv func(string) string) (serverConfig, error) {
+	cfg := serverConfig{
+		Port:           "8080",
+		FilepathRoot:   ".",
+		ReadTimeout:    10 * time.Second,
+		WriteTimeout:   30 * time.Second,
+		IdleTimeout:    120 * time.Second,
+		MaxHeaderBytes: http.DefaultMaxHeaderBytes,
+	}
+	var problems []string
+
+	if raw := getenv("PORT"); raw != "" {
+		if port, err := strconv.Atoi(raw); err != nil || port < 1 || port > 65535 {
+			problems = append(problems, fmt.Sprintf("PORT must be a number between 1 and 65535, got %q", raw))
+		} else {
+			cfg.Port = strconv.Itoa(port)
+		}
+	}
+	if raw := getenv("FILEPATH_ROOT"); raw != "" {
+		cfg.FilepathRoot = raw
+	}
+	for _, d := range []struct {
+		name string
+		dst  *time.Duration
+	}{
+		{"READ_TIMEOUT", &cfg.ReadTimeout},
+		{"WRITE_TIMEOUT", &cfg.WriteTimeout},
+		{"IDLE_TIMEOUT", &cfg.IdleTimeout},
+	} {
+		raw := getenv(d.name)
+		if raw == "" {
+			continue
+		}
+		value, err := time.ParseDuration(raw)
+		if err != nil || value <= 0 {
+			problems = append(problems, fmt.Sprintf("%s must be a positive duration like 30s, got %q", d.name, raw))
+			continue
+		}
+		*d.dst = value
+	}
+	if raw := getenv("MAX_HEADER_BYTES"); raw != "" {
+		if n, err := strconv.Atoi(raw); err != nil || n <= 0 {
+			problems = append(problems, fmt.Sprintf("MAX_HEADER_BYTES must be a positive number of bytes, got %q", raw))
+		} else {
+			cfg.MaxHeaderBytes = n
+		}
+	}
+
+	if len(problems) > 0 {
+		return serverConfig{}, errors.New(strings.Join(problems, "; "))
+	}
+	return cfg, nil
+}
+
+// Baut den http.Server aus der Konfiguration.
+func (c serverConfig) newServer(handler http.Handler) *http.Server {
+	return &http.Server{
+		Addr:           ":" + c.Port,
+		Handler:        handler,
+		ReadTimeout:    c.ReadTimeout,
+		WriteTimeout:   c.WriteTimeout,
+		IdleTimeout:    c.IdleTimeout,
+		MaxHeaderBytes: c.MaxHeaderBytes,
+	}
+}
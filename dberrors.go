@@ -0,0 +1,19 @@
+package main
+
+import (
+	"errors"
+
+	"github.com/lib/pq"
+)
+
+// Meldet, ob err eine Postgres-Unique-Verletzung (23505) des genannten Constraints ist.
+func isUniqueViolation(err error, constraint string) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "23505" && pqErr.Constraint == constraint
+}
+
+// Meldet, ob err eine Postgres-Fremdschlüssel-Verletzung (23503) des genannten Constraints ist.
+func isForeignKeyViolation(err error, constraint string) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "23503" && pqErr.Constraint == constraint
+}
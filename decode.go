@@ -0,0 +1,214 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/nuke87/go_http_server/pkg/chirpy"
+)
+
+// Fehlercodes für ungültige Request-Bodies
+const (
+	errCodeEmptyBody      = "empty_body"
+	errCodeMalformedJSON  = "malformed_json"
+	errCodeMissingField   = "missing_field"
+	errCodeUnknownField   = "unknown_field"
+	errCodeDuplicateField = "duplicate_field"
+	errCodeInvalidType    = "invalid_type"
+	errCodeOutOfRange     = "out_of_range"
+	errCodeInvalidField   = "invalid_field"
+)
+
+// Höchstgröße eines (entpackten) Request-Bodies, einstellbar über MAX_REQUEST_BODY_BYTES.
+var maxRequestBodyBytes int64 = 1 << 20
+
+// requestError beschreibt einen fehlerhaften Request samt HTTP-Status und Fehlercode.
+type requestError struct {
+	status int
+	code   string
+	field  string // Betroffenes Feld des Requests, falls bekannt
+	msg    string
+	err    error
+}
+
+func (e *requestError) Error() string {
+	return e.msg
+}
+
+// Dekodiert den JSON-Body nach dst. Der Content-Type muss application/json sein (sonst 415),
+// der Body wird per http.MaxBytesReader auf maxRequestBodyBytes begrenzt (sonst 413).
+// Ein leerer Body, nur Whitespace und ein literales "null" gelten als empty_body, kaputtes
+// JSON als malformed_json, Felder, die dst nicht kennt, als unknown_field.
+func decodeJSONBody(w http.ResponseWriter, r *http.Request, dst interface{}) *requestError {
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil || mediaType != "application/json" {
+		return &requestError{status: http.StatusUnsupportedMediaType, msg: "Content-Type must be application/json"}
+	}
+
+	data, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxRequestBodyBytes))
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		return &requestError{status: http.StatusRequestEntityTooLarge, msg: fmt.Sprintf("Request body exceeds %d bytes", maxBytesErr.Limit)}
+	}
+	if errors.Is(err, errInvalidEncoding) {
+		return &requestError{status: http.StatusBadRequest, code: errCodeInvalidEncoding, msg: "Request body is not valid gzip", err: err}
+	}
+	if err != nil {
+		return &requestError{status: http.StatusBadRequest, code: errCodeMalformedJSON, msg: "Couldn't read request body", err: err}
+	}
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 || string(data) == "null" {
+		return &requestError{status: http.StatusBadRequest, code: errCodeEmptyBody, msg: "Request body is empty"}
+	}
+
+	if field := duplicateTopLevelKey(data); field != "" {
+		return &requestError{status: http.StatusBadRequest, code: errCodeDuplicateField, field: field, msg: fmt.Sprintf("Field %q appears more than once", field)}
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(dst); err != nil {
+		// encoding/json hat für unbekannte Felder keinen eigenen Fehlertyp.
+		if field, ok := strings.CutPrefix(err.Error(), "json: unknown field "); ok {
+			field = strings.Trim(field, `"`)
+			return &requestError{status: http.StatusBadRequest, code: errCodeUnknownField, field: field, msg: fmt.Sprintf("Unknown field %q", field)}
+		}
+		var typeErr *json.UnmarshalTypeError
+		if errors.As(err, &typeErr) && typeErr.Field != "" {
+			return &requestError{status: http.StatusBadRequest, code: errCodeInvalidType, field: typeErr.Field, msg: fmt.Sprintf("%s must be of type %s, got %s", typeErr.Field, jsonTypeName(typeErr.Type), typeErr.Value), err: err}
+		}
+		return &requestError{status: http.StatusBadRequest, code: errCodeMalformedJSON, msg: "Request body is not valid JSON", err: err}
+	}
+	if dec.More() {
+		return &requestError{status: http.StatusBadRequest, code: errCodeMalformedJSON, msg: "Request body contains more than one JSON value"}
+	}
+	return nil
+}
+
+// Sucht per Token-Scan den ersten Schlüssel, der im obersten JSON-Objekt doppelt vorkommt.
+// encoding/json nimmt sonst stillschweigend den letzten Wert. Kein Objekt oder kaputtes
+// JSON ergibt "", das meldet danach der eigentliche Decoder.
+func duplicateTopLevelKey(data []byte) string {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('{') {
+		return ""
+	}
+	seen := map[string]bool{}
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return ""
+		}
+		key, _ := tok.(string)
+		if seen[key] {
+			return key
+		}
+		seen[key] = true
+		// Wert überspringen, egal wie tief verschachtelt
+		var skip json.RawMessage
+		if err := dec.Decode(&skip); err != nil {
+			return ""
+		}
+	}
+	return ""
+}
+
+// JSON-Name eines Go-Typs für Fehlermeldungen
+func jsonTypeName(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.String:
+		return "string"
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "integer"
+	case reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.Slice, reflect.Array:
+		return "array"
+	}
+	return "object"
+}
+
+// jsonNumber ist ein Zahlenfeld im Request. Der Rohwert bleibt erhalten und wird erst mit
+// Int geprüft, damit Überläufe wie 1e300 und Zahlen in Anführungszeichen einen Fehler für
+// das Feld ergeben statt eines allgemeinen Unmarshal-Fehlers. Verwendet für
+// expires_in_seconds (Login) und offset_seconds (Time-Travel); Chirps und Profil haben
+// keine Zahlenfelder.
+type jsonNumber json.RawMessage
+
+func (n *jsonNumber) UnmarshalJSON(b []byte) error {
+	*n = append((*n)[:0], b...)
+	return nil
+}
+
+// Meldet, ob das Feld im Request stand und nicht null war.
+func (n jsonNumber) isSet() bool {
+	raw := string(n)
+	return raw != "" && raw != "null"
+}
+
+// Liefert den Wert als ganze Zahl in [min, max]; sonst ein Fehler für das Feld. Brüche und
+// Exponenten wie 1.5 oder 1e400 sind invalid_type, ganze Zahlen außerhalb out_of_range.
+// Fehlt das Feld oder ist es null, ist das Ergebnis 0.
+func (n jsonNumber) Int(field string, min, max int64) (int64, *requestError) {
+	if !n.isSet() {
+		return 0, nil
+	}
+	raw := string(n)
+	dec := json.NewDecoder(strings.NewReader(raw))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return 0, &requestError{status: http.StatusBadRequest, code: errCodeInvalidType, field: field, msg: fmt.Sprintf("%s must be a number", field)}
+	}
+	num, ok := v.(json.Number)
+	if !ok {
+		return 0, &requestError{status: http.StatusBadRequest, code: errCodeInvalidType, field: field, msg: fmt.Sprintf("%s must be a number, got %s", field, jsonValueKind(v))}
+	}
+	if strings.ContainsAny(num.String(), ".eE") {
+		return 0, &requestError{status: http.StatusBadRequest, code: errCodeInvalidType, field: field, msg: fmt.Sprintf("%s must be an integer, got %s", field, num)}
+	}
+	i, err := strconv.ParseInt(num.String(), 10, 64)
+	if err != nil || i < min || i > max {
+		return 0, &requestError{status: http.StatusBadRequest, code: errCodeOutOfRange, field: field, msg: fmt.Sprintf("%s must be an integer between %d and %d", field, min, max)}
+	}
+	return i, nil
+}
+
+func jsonValueKind(v any) string {
+	switch v.(type) {
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case []any:
+		return "array"
+	}
+	return "object"
+}
+
+// Antwortet mit dem Status, Code, Feld und der Meldung eines requestError.
+func respondWithRequestError(w http.ResponseWriter, reqErr *requestError) {
+	if reqErr.err != nil {
+		log.Println(reqErr.err)
+	}
+	if reqErr.status > 499 {
+		log.Printf("Responding with 5XX error: %s", reqErr.msg)
+	}
+	respondWithJSON(w, reqErr.status, chirpy.ErrorResponse{
+		Error: reqErr.msg,
+		Code:  reqErr.code,
+		Field: reqErr.field,
+	})
+}
@@ -0,0 +1,186 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/nuke87/go_http_server/pkg/chirpy"
+)
+
+// bodyEndpoint ist eine Route, die einen JSON-Body erwartet.
+type bodyEndpoint struct {
+	method, path  string
+	authorization string // Kompletter Authorization-Header, leer für öffentliche Routen
+	emptyObject   int    // Erwarteter Status für "{}": Validierung des Handlers, nicht empty_body
+}
+
+// Alle POST/PUT-Routen mit JSON-Body, vorbereitet mit User, Chirp und Vorlage.
+func bodyEndpoints(t *testing.T, ts *testServer) []bodyEndpoint {
+	t.Helper()
+	_, token := ts.createUser(t, "alice@example.com")
+	chirp := ts.createChirp(t, token, "original")
+	rec := ts.do(t, "POST", "/api/users/me/templates", token, `{"name":"greeting","body":"hi {{name}}"}`)
+	expectStatus(t, rec, http.StatusCreated)
+	template := decodeResponse[chirpy.ChirpTemplate](t, rec)
+
+	bearer := "Bearer " + token
+	return []bodyEndpoint{
+		{"POST", "/api/users", "", http.StatusBadRequest},
+		{"POST", "/api/login", "", http.StatusUnauthorized},
+		{"POST", "/api/chirps", bearer, http.StatusBadRequest},
+		{"PUT", "/api/chirps/" + chirp.ID.String(), bearer, http.StatusBadRequest},
+		{"PUT", "/api/users/me/profile", bearer, http.StatusOK},
+		{"POST", "/api/users/me/templates", bearer, http.StatusBadRequest},
+		{"PUT", "/api/users/me/templates/" + template.ID.String(), bearer, http.StatusBadRequest},
+		{"POST", "/api/polka/webhooks", "ApiKey " + ts.cfg.polkaKey, http.StatusNoContent},
+		{"POST", "/admin/auth/diagnose", "", http.StatusBadRequest},
+		{"POST", "/admin/testing/time-travel", "", http.StatusBadRequest},
+	}
+}
+
+// Schickt body mit Content-Type application/json, auch wenn er leer ist.
+func (ts *testServer) sendBody(ep bodyEndpoint, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(ep.method, ep.path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	if ep.authorization != "" {
+		req.Header.Set("Authorization", ep.authorization)
+	}
+	return ts.serve(req)
+}
+
+func TestDecodeJSONBodyEmptyBodies(t *testing.T) {
+	ts := newTestServer(t)
+	for _, ep := range bodyEndpoints(t, ts) {
+		t.Run(ep.method+" "+ep.path, func(t *testing.T) {
+			for _, body := range []string{"", "   ", "\n\t\r\n", "null", " null\n"} {
+				rec := ts.sendBody(ep, body)
+				expectStatus(t, rec, http.StatusBadRequest)
+				if got := decodeResponse[chirpy.ErrorResponse](t, rec); got.Code != errCodeEmptyBody {
+					t.Errorf("body %q: code = %q, want %q", body, got.Code, errCodeEmptyBody)
+				}
+			}
+
+			rec := ts.sendBody(ep, "{")
+			expectStatus(t, rec, http.StatusBadRequest)
+			if got := decodeResponse[chirpy.ErrorResponse](t, rec); got.Code != errCodeMalformedJSON {
+				t.Errorf("body %q: code = %q, want %q", "{", got.Code, errCodeMalformedJSON)
+			}
+
+			// Ein leeres Objekt ist kein leerer Body: Es entscheidet die Validierung des Handlers
+			rec = ts.sendBody(ep, "{}")
+			if rec.Code != ep.emptyObject {
+				t.Fatalf("body {}: status = %d, want %d, body %s", rec.Code, ep.emptyObject, rec.Body)
+			}
+			if rec.Code == http.StatusBadRequest {
+				if got := decodeResponse[chirpy.ErrorResponse](t, rec); got.Code == errCodeEmptyBody {
+					t.Errorf("body {}: code = %q", got.Code)
+				}
+			}
+		})
+	}
+}
+
+// POST/PUT-Routen ohne Request-Body
+var bodylessRoutes = map[string]bool{
+	"POST /api/users/{userID}/follow": true,
+	"POST /api/refresh":               true,
+	"POST /api/revoke":                true,
+	"POST /api/chirps/{chirpID}/like": true,
+	"POST /admin/reset":               true,
+}
+
+// Neue POST/PUT-Routen müssen in bodyEndpoints oder bodylessRoutes eingetragen werden.
+func TestBodyEndpointsCoverRouteTable(t *testing.T) {
+	ts := newTestServer(t)
+	covered := map[string]bool{}
+	for _, ep := range bodyEndpoints(t, ts) {
+		covered[ep.method+" "+ep.path] = true
+	}
+	for _, r := range ts.cfg.routes(t.TempDir()) {
+		if r.Method != "POST" && r.Method != "PUT" {
+			continue
+		}
+		key := r.Method + " " + r.Pattern
+		if bodylessRoutes[key] {
+			continue
+		}
+		// Pfadparameter wurden in bodyEndpoints durch echte IDs ersetzt
+		found := covered[key]
+		prefix, _, hasParam := strings.Cut(key, "{")
+		for c := range covered {
+			if hasParam && strings.HasPrefix(c, prefix) && strings.Count(c, "/") == strings.Count(key, "/") {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("%s is missing from bodyEndpoints (or bodylessRoutes)", key)
+		}
+	}
+}
+
+func TestJSONNumberInt(t *testing.T) {
+	tests := []struct {
+		raw      string
+		want     int64
+		wantCode string
+	}{
+		{"", 0, ""},
+		{"null", 0, ""},
+		{"0", 0, ""},
+		{"100", 100, ""},
+		{"-5", 0, errCodeOutOfRange},
+		{"101", 0, errCodeOutOfRange},
+		{"99999999999999999999", 0, errCodeOutOfRange},
+		{"1.5", 0, errCodeInvalidType},
+		{"1.0", 0, errCodeInvalidType},
+		{"1e2", 0, errCodeInvalidType},
+		{"1e400", 0, errCodeInvalidType},
+		{`"10"`, 0, errCodeInvalidType},
+		{"true", 0, errCodeInvalidType},
+		{"[1]", 0, errCodeInvalidType},
+	}
+	for _, tt := range tests {
+		got, reqErr := jsonNumber(tt.raw).Int("n", 0, 100)
+		var code string
+		if reqErr != nil {
+			code = reqErr.code
+			if reqErr.field != "n" {
+				t.Errorf("Int(%s): field = %q, want n", tt.raw, reqErr.field)
+			}
+		}
+		if got != tt.want || code != tt.wantCode {
+			t.Errorf("Int(%s) = %d, code %q; want %d, code %q", tt.raw, got, code, tt.want, tt.wantCode)
+		}
+	}
+}
+
+// Zahlenfelder im Request werden über jsonNumber strikt geprüft.
+func TestStrictNumberFields(t *testing.T) {
+	ts := newTestServer(t)
+	ts.createUser(t, "alice@example.com")
+	tests := []struct {
+		path, body, field string
+		wantCode          string
+	}{
+		{"/api/login", `{"email":"alice@example.com","password":"hunter22","expires_in_seconds":1.5}`, "expires_in_seconds", errCodeInvalidType},
+		{"/api/login", `{"email":"alice@example.com","password":"hunter22","expires_in_seconds":1e400}`, "expires_in_seconds", errCodeInvalidType},
+		{"/api/login", `{"email":"alice@example.com","password":"hunter22","expires_in_seconds":-1}`, "expires_in_seconds", errCodeOutOfRange},
+		{"/admin/testing/time-travel", `{"offset_seconds":1.5}`, "offset_seconds", errCodeInvalidType},
+		{"/admin/testing/time-travel", `{"offset_seconds":1e400}`, "offset_seconds", errCodeInvalidType},
+		{"/admin/testing/time-travel", `{"offset_seconds":"60"}`, "offset_seconds", errCodeInvalidType},
+		{"/admin/testing/time-travel", `{"offset_seconds":99999999999999999999}`, "offset_seconds", errCodeOutOfRange},
+		{"/admin/testing/time-travel", `{"offset_seconds":null}`, "", errCodeMissingField},
+	}
+	for _, tt := range tests {
+		rec := ts.do(t, "POST", tt.path, "", tt.body)
+		expectStatus(t, rec, http.StatusBadRequest)
+		if got := decodeResponse[chirpy.ErrorResponse](t, rec); got.Code != tt.wantCode || got.Field != tt.field {
+			t.Errorf("POST %s %s: code %q field %q, want %q %q", tt.path, tt.body, got.Code, got.Field, tt.wantCode, tt.field)
+		}
+	}
+	if !ts.cfg.clock.Now().Equal(ts.now) {
+		t.Errorf("clock moved to %s", ts.cfg.clock.Now())
+	}
+}
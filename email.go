@@ -0,0 +1,31 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/mail"
+	"strings"
+)
+
+const (
+	maxEmailLength = 254 // RFC 5321: Pfad höchstens 256 Oktette inkl. <>
+
+	errCodeInvalidEmail = "invalid_email"
+)
+
+// Prüft und normalisiert eine E-Mail-Adresse: Whitespace am Rand wird entfernt, die
+// Adresse muss für net/mail gültig sein und darf keinen Anzeigenamen ("Name <a@b>")
+// enthalten. Die Domain wird kleingeschrieben, der lokale Teil bleibt unverändert,
+// da er laut RFC case-sensitive sein darf.
+func normalizeEmail(email string) (string, *requestError) {
+	email = strings.TrimSpace(email)
+	if len(email) > maxEmailLength {
+		return "", &requestError{status: http.StatusBadRequest, code: errCodeInvalidEmail, field: "email", msg: fmt.Sprintf("email must be at most %d characters", maxEmailLength)}
+	}
+	addr, err := mail.ParseAddress(email)
+	if err != nil || addr.Name != "" || addr.Address != email {
+		return "", &requestError{status: http.StatusBadRequest, code: errCodeInvalidEmail, field: "email", msg: "email is not a valid email address"}
+	}
+	at := strings.LastIndex(addr.Address, "@")
+	return addr.Address[:at] + "@" + strings.ToLower(addr.Address[at+1:]), nil
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+const errCodeInvalidEncoding = "invalid_encoding"
+
+// Wird beim Lesen eines Request-Bodies geliefert, dessen gzip-Strom kaputt ist.
+var errInvalidEncoding = errors.New("invalid gzip request body")
+
+// Middleware: Entpackt Request-Bodies mit Content-Encoding: gzip für POST/PUT/PATCH unter /api,
+// bevor sie dekodiert werden. Die entpackte Größe wird gegen maxRequestBodyBytes geprüft,
+// damit eine Zip-Bombe das Body-Limit nicht umgehen kann. Andere Encodings gibt es nur
+// als Antwort, nicht im Request: 415.
+func middlewareGzipRequest(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		encoding := strings.ToLower(strings.TrimSpace(r.Header.Get("Content-Encoding")))
+		if encoding == "" || encoding == "identity" || !strings.HasPrefix(r.URL.Path, "/api/") ||
+			(r.Method != http.MethodPost && r.Method != http.MethodPut && r.Method != http.MethodPatch) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if encoding != "gzip" {
+			respondWithError(w, http.StatusUnsupportedMediaType, fmt.Sprintf("Unsupported Content-Encoding %q, only gzip is accepted", encoding), nil)
+			return
+		}
+
+		zr, err := gzip.NewReader(r.Body)
+		if err != nil {
+			respondWithErrorCode(w, http.StatusBadRequest, errCodeInvalidEncoding, "Request body is not valid gzip", err)
+			return
+		}
+		r.Body = &gzipBody{zr: zr, orig: r.Body, remaining: maxRequestBodyBytes}
+		r.Header.Del("Content-Encoding")
+		r.Header.Del("Content-Length")
+		r.ContentLength = -1
+		next.ServeHTTP(w, r)
+	})
+}
+
+// gzipBody liest entpackte Daten und bricht ab, sobald das Body-Limit überschritten ist.
+type gzipBody struct {
+	zr        *gzip.Reader
+	orig      io.ReadCloser
+	remaining int64
+}
+
+func (b *gzipBody) Read(p []byte) (int, error) {
+	if b.remaining < 0 {
+		return 0, &http.MaxBytesError{Limit: maxRequestBodyBytes}
+	}
+	// Ein Byte mehr als erlaubt anfordern, um Überschreitungen zu erkennen.
+	if int64(len(p)) > b.remaining+1 {
+		p = p[:b.remaining+1]
+	}
+	n, err := b.zr.Read(p)
+	b.remaining -= int64(n)
+	if b.remaining < 0 {
+		return n, &http.MaxBytesError{Limit: maxRequestBodyBytes}
+	}
+	if err != nil && err != io.EOF {
+		return n, fmt.Errorf("%w: %s", errInvalidEncoding, err)
+	}
+	return n, err
+}
+
+func (b *gzipBody) Close() error {
+	b.zr.Close()
+	return b.orig.Close()
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/nuke87/go_http_server/pkg/chirpy"
+)
+
+func gzipData(t *testing.T, data string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write([]byte(data)); err != nil {
+		t.Fatal(err)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestGzipRequestBody(t *testing.T) {
+	valid := gzipData(t, `{"body":"compressed hello"}`)
+	// Entpackt doppelt so groß wie das Body-Limit, gepackt nur ein paar KB
+	bomb := gzipData(t, `{"body":"`+strings.Repeat("a", int(2*maxRequestBodyBytes))+`"}`)
+
+	tests := []struct {
+		name     string
+		encoding string
+		body     []byte
+		want     int
+		wantCode string
+	}{
+		{"valid gzip", "gzip", valid, http.StatusCreated, ""},
+		{"encoding is case-insensitive", " GZIP ", valid, http.StatusCreated, ""},
+		{"truncated stream", "gzip", valid[:len(valid)-10], http.StatusBadRequest, errCodeInvalidEncoding},
+		{"not gzip at all", "gzip", []byte(`{"body":"plain"}`), http.StatusBadRequest, errCodeInvalidEncoding},
+		{"zip bomb", "gzip", bomb, http.StatusRequestEntityTooLarge, ""},
+		{"brotli", "br", valid, http.StatusUnsupportedMediaType, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if len(tt.body) > 64<<10 {
+				t.Fatalf("test payload is %d bytes compressed, expected a small bomb", len(tt.body))
+			}
+			ts := newTestServer(t)
+			_, token := ts.createUser(t, "alice@example.com")
+			req := httptest.NewRequest("POST", "/api/chirps", bytes.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("Content-Encoding", tt.encoding)
+			req.Header.Set("Authorization", "Bearer "+token)
+			rec := ts.serve(req)
+			expectStatus(t, rec, tt.want)
+			if tt.want == http.StatusCreated {
+				if got := decodeResponse[chirpy.Chirp](t, rec); got.Body != "compressed hello" {
+					t.Errorf("body = %q", got.Body)
+				}
+				return
+			}
+			if got := decodeResponse[chirpy.ErrorResponse](t, rec); got.Code != tt.wantCode {
+				t.Errorf("code = %q, want %q", got.Code, tt.wantCode)
+			}
+		})
+	}
+}
+
+// Ohne /api-Präfix oder bei GET bleibt der Body unangetastet.
+func TestGzipRequestBodyOnlyForAPIWrites(t *testing.T) {
+	var gotEncoding string
+	h := middlewareGzipRequest(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotEncoding = r.Header.Get("Content-Encoding")
+	}))
+	for _, tt := range []struct{ method, path string }{
+		{"GET", "/api/chirps"},
+		{"POST", "/app/upload"},
+	} {
+		gotEncoding = ""
+		req := httptest.NewRequest(tt.method, tt.path, strings.NewReader("raw"))
+		req.Header.Set("Content-Encoding", "br")
+		h.ServeHTTP(httptest.NewRecorder(), req)
+		if gotEncoding != "br" {
+			t.Errorf("%s %s: Content-Encoding = %q, want passed through", tt.method, tt.path, gotEncoding)
+		}
+	}
+}
@@ -0,0 +1,27 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/nuke87/go_http_server/internal/auth"
+)
+
+// Handler für /admin/auth/diagnose (POST)
+// Erwartet {"token": "..."} und meldet, an welchem Prüfschritt das Token scheitert.
+// Das Token wird nie geloggt. Zugang regelt middlewareAdmin.
+func (cfg *apiConfig) handlerAuthDiagnose(w http.ResponseWriter, r *http.Request) {
+	type parameters struct {
+		Token string `json:"token"`
+	}
+	var params parameters
+	if reqErr := decodeJSONBody(w, r, &params); reqErr != nil {
+		respondWithRequestError(w, reqErr)
+		return
+	}
+	if params.Token == "" {
+		respondWithErrorCode(w, http.StatusBadRequest, errCodeMissingField, "token is required", nil)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, auth.DiagnoseJWT(params.Token, cfg.jwtSecret, cfg.clock.Now()))
+}
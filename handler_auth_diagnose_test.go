@@ -0,0 +1,42 @@
+package main
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/nuke87/go_http_server/internal/auth"
+)
+
+// Außerhalb von dev ist die Diagnose mit ADMIN_TOKEN erreichbar.
+func TestAuthDiagnoseWithAdminToken(t *testing.T) {
+	ts := newTestServer(t, func(cfg *apiConfig) {
+		cfg.platform = "production"
+		cfg.adminToken = "admin-secret"
+	})
+	userToken := ts.token(t, uuid.New())
+
+	tests := []struct {
+		name      string
+		token     string
+		wantValid bool
+		wantStep  string
+	}{
+		{"valid", userToken, true, ""},
+		{"bad signature", userToken[:len(userToken)-2] + "xx", false, "signature"},
+		{"not a JWT", "nope", false, "format"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := ts.do(t, "POST", "/admin/auth/diagnose", "admin-secret", `{"token":"`+tt.token+`"}`)
+			expectStatus(t, rec, http.StatusOK)
+			got := decodeResponse[auth.Diagnosis](t, rec)
+			if got.Valid != tt.wantValid || got.Step != tt.wantStep {
+				t.Errorf("diagnosis = %+v, want valid %v step %q", got, tt.wantValid, tt.wantStep)
+			}
+		})
+	}
+
+	expectStatus(t, ts.do(t, "POST", "/admin/auth/diagnose", "", `{"token":"`+userToken+`"}`), http.StatusUnauthorized)
+	expectStatus(t, ts.do(t, "POST", "/admin/auth/diagnose", userToken, `{"token":"`+userToken+`"}`), http.StatusForbidden)
+}
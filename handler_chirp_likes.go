@@ -0,0 +1,67 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/nuke87/go_http_server/internal/database"
+)
+
+// Handler für /api/chirps/{chirpID}/like (POST)
+// Liked den Chirp als angemeldeter User und gibt ihn mit neuem like_count zurück.
+// Ein zweites Like ändert nichts und liefert ebenfalls 200; 404 wenn es den Chirp nicht gibt,
+// 401 wenn der User hinter dem Token gelöscht wurde.
+func (cfg *apiConfig) handlerChirpLike(w http.ResponseWriter, r *http.Request) {
+	chirp, reqErr := cfg.chirpFromPath(r)
+	if reqErr != nil {
+		respondWithRequestError(w, reqErr)
+		return
+	}
+
+	err := cfg.db.LikeChirp(r.Context(), database.LikeChirpParams{
+		ChirpID:   chirp.ID,
+		UserID:    userIDFromContext(r.Context()),
+		CreatedAt: cfg.clock.Now().UTC(),
+	})
+	if isForeignKeyViolation(err, "chirp_likes_chirp_id_fkey") {
+		// Der Chirp wurde seit dem Laden gelöscht
+		respondWithError(w, http.StatusNotFound, "Chirp not found", nil)
+		return
+	}
+	if isForeignKeyViolation(err, "chirp_likes_user_id_fkey") {
+		// Gültiges Token, aber der User wurde inzwischen gelöscht
+		respondWithError(w, http.StatusUnauthorized, "User no longer exists", nil)
+		return
+	}
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't like chirp", err)
+		return
+	}
+
+	chirpResp, err := cfg.chirpJSONFor(r, chirp)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't retrieve chirp", err)
+		return
+	}
+	respondWithJSON(w, http.StatusOK, chirpResp)
+}
+
+// Handler für /api/chirps/{chirpID}/like (DELETE)
+// Entfernt das Like des angemeldeten Users; auch ohne vorheriges Like 204.
+func (cfg *apiConfig) handlerChirpUnlike(w http.ResponseWriter, r *http.Request) {
+	chirp, reqErr := cfg.chirpFromPath(r)
+	if reqErr != nil {
+		respondWithRequestError(w, reqErr)
+		return
+	}
+
+	_, err := cfg.db.UnlikeChirp(r.Context(), database.UnlikeChirpParams{
+		ChirpID: chirp.ID,
+		UserID:  userIDFromContext(r.Context()),
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't unlike chirp", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/nuke87/go_http_server/pkg/chirpy"
+)
+
+func TestChirpLike(t *testing.T) {
+	ts := newTestServer(t)
+	_, aliceToken := ts.createUser(t, "alice@example.com")
+	_, bobToken := ts.createUser(t, "bob@example.com")
+	chirp := ts.createChirp(t, bobToken, "like me")
+	path := "/api/chirps/" + chirp.ID.String() + "/like"
+
+	// Ein zweites Like zählt nicht doppelt
+	for i := 0; i < 2; i++ {
+		rec := ts.do(t, "POST", path, aliceToken, "")
+		expectStatus(t, rec, http.StatusOK)
+		got := decodeResponse[chirpy.Chirp](t, rec)
+		if got.LikeCount != 1 || got.LikedByMe == nil || !*got.LikedByMe {
+			t.Errorf("like #%d: like_count %d, liked_by_me %v", i+1, got.LikeCount, got.LikedByMe)
+		}
+	}
+
+	expectStatus(t, ts.do(t, "POST", "/api/chirps/"+uuid.NewString()+"/like", aliceToken, ""), http.StatusNotFound)
+	expectStatus(t, ts.do(t, "POST", path, "", ""), http.StatusUnauthorized)
+}
+
+// Ein noch gültiges Token eines gelöschten Users ist 401, kein 500 aus dem Foreign Key.
+func TestDeletedUserTokenOnLikeAndFollow(t *testing.T) {
+	ts := newTestServer(t)
+	alice, aliceToken := ts.createUser(t, "alice@example.com")
+	bob, bobToken := ts.createUser(t, "bob@example.com")
+	chirp := ts.createChirp(t, bobToken, "like me")
+	if _, err := ts.store.DeleteUser(context.Background(), alice.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	expectStatus(t, ts.do(t, "POST", "/api/chirps/"+chirp.ID.String()+"/like", aliceToken, ""), http.StatusUnauthorized)
+	expectStatus(t, ts.do(t, "POST", "/api/users/"+bob.ID.String()+"/follow", aliceToken, ""), http.StatusUnauthorized)
+}
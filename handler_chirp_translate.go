@@ -0,0 +1,85 @@
+package main
+
+import (
+	"database/sql"
+	"errors"
+	"log"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/nuke87/go_http_server/internal/database"
+)
+
+// Handler für /api/chirps/{chirpID}/translate?to=de (GET)
+// Übersetzt einen Chirp über den konfigurierten Translator. Ergebnisse werden pro
+// (Chirp, Sprache) in der DB gespeichert, Wiederholungen kosten also nichts. Regionen
+// werden auf die Basissprache reduziert (de-AT → de), erlaubt ist nur TRANSLATE_LANGUAGES.
+func (cfg *apiConfig) handlerChirpTranslate(w http.ResponseWriter, r *http.Request) {
+	lang, err := translateBaseLanguage(r.URL.Query().Get("to"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Query parameter 'to' must be a language tag, e.g. de", err)
+		return
+	}
+	if !slices.Contains(cfg.translateLangs, lang) {
+		respondWithError(w, http.StatusBadRequest, "Query parameter 'to' must be one of: "+strings.Join(cfg.translateLangs, ", "), nil)
+		return
+	}
+
+	chirp, reqErr := cfg.chirpFromPath(r)
+	if reqErr != nil {
+		respondWithRequestError(w, reqErr)
+		return
+	}
+
+	type response struct {
+		TranslatedBody string `json:"translated_body"`
+		SourceLang     string `json:"source_lang"`
+		Provider       string `json:"provider"`
+	}
+
+	cached, err := cfg.db.GetChirpTranslation(r.Context(), database.GetChirpTranslationParams{
+		ChirpID: chirp.ID,
+		Lang:    lang,
+	})
+	if err == nil {
+		respondWithJSON(w, http.StatusOK, response{
+			TranslatedBody: cached.TranslatedBody,
+			SourceLang:     cached.SourceLang,
+			Provider:       cached.Provider,
+		})
+		return
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't read translation cache", err)
+		return
+	}
+
+	result, err := cfg.translator.Translate(r.Context(), chirp.Body, lang)
+	if errors.Is(err, errTranslationUnavailable) {
+		respondWithError(w, http.StatusNotImplemented, "Translation is not configured", nil)
+		return
+	}
+	if err != nil {
+		respondWithError(w, http.StatusBadGateway, "Translation provider failed", err)
+		return
+	}
+
+	err = cfg.db.CreateChirpTranslation(r.Context(), database.CreateChirpTranslationParams{
+		ChirpID:        chirp.ID,
+		Lang:           lang,
+		TranslatedBody: result.Text,
+		SourceLang:     result.SourceLang,
+		Provider:       cfg.translator.Name(),
+	})
+	if err != nil {
+		// Die Übersetzung liegt vor; ein fehlgeschlagener Cache-Eintrag wird nur geloggt.
+		log.Printf("Couldn't cache translation: %s", err)
+	}
+
+	respondWithJSON(w, http.StatusOK, response{
+		TranslatedBody: result.Text,
+		SourceLang:     result.SourceLang,
+		Provider:       cfg.translator.Name(),
+	})
+}
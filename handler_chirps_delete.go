@@ -0,0 +1,24 @@
+package main
+
+import "net/http"
+
+// Handler für /api/chirps/{chirpID} (DELETE)
+// Nur der Autor darf seinen Chirp löschen: 404 wenn es ihn nicht gibt, 403 für andere User.
+func (cfg *apiConfig) handlerChirpDelete(w http.ResponseWriter, r *http.Request) {
+	chirp, reqErr := cfg.chirpFromPath(r)
+	if reqErr != nil {
+		respondWithRequestError(w, reqErr)
+		return
+	}
+	if chirp.UserID != userIDFromContext(r.Context()) {
+		respondWithError(w, http.StatusForbidden, "You can't delete this chirp", nil)
+		return
+	}
+
+	if err := cfg.db.DeleteChirp(r.Context(), chirp.ID); err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't delete chirp", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
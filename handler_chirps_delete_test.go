@@ -0,0 +1,51 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestChirpDelete(t *testing.T) {
+	ts := newTestServer(t)
+	_, aliceToken := ts.createUser(t, "alice@example.com")
+	_, bobToken := ts.createUser(t, "bob@example.com")
+	chirp := ts.createChirp(t, aliceToken, "delete me")
+	path := "/api/chirps/" + chirp.ID.String()
+
+	t.Run("non-owner", func(t *testing.T) {
+		rec := ts.do(t, "DELETE", path, bobToken, "")
+		expectStatus(t, rec, http.StatusForbidden)
+		if _, err := ts.store.GetChirpByID(context.Background(), chirp.ID); err != nil {
+			t.Errorf("chirp gone after forbidden delete: %v", err)
+		}
+	})
+
+	t.Run("without token", func(t *testing.T) {
+		rec := ts.do(t, "DELETE", path, "", "")
+		expectStatus(t, rec, http.StatusUnauthorized)
+	})
+
+	t.Run("owner", func(t *testing.T) {
+		rec := ts.do(t, "DELETE", path, aliceToken, "")
+		if rec.Code != http.StatusNoContent {
+			t.Fatalf("status = %d, want 204, body %s", rec.Code, rec.Body)
+		}
+		if rec.Body.Len() != 0 {
+			t.Errorf("204 with body %q", rec.Body)
+		}
+		if _, err := ts.store.GetChirpByID(context.Background(), chirp.ID); !errors.Is(err, sql.ErrNoRows) {
+			t.Errorf("GetChirpByID after delete: err = %v, want sql.ErrNoRows", err)
+		}
+	})
+
+	t.Run("missing chirp", func(t *testing.T) {
+		// Der eben gelöschte Chirp und eine nie vergebene ID verhalten sich gleich
+		expectStatus(t, ts.do(t, "DELETE", path, aliceToken, ""), http.StatusNotFound)
+		expectStatus(t, ts.do(t, "DELETE", "/api/chirps/"+uuid.NewString(), aliceToken, ""), http.StatusNotFound)
+	})
+}
@@ -0,0 +1,284 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/google/uuid"
+
+	"github.com/nuke87/go_http_server/internal/database"
+	"github.com/nuke87/go_http_server/pkg/chirpy"
+)
+
+// Handler für /api/chirps (GET)
+// Gibt alle Chirps nach created_at sortiert zurück, bei leerer DB ein leeres Array.
+// Mit ?author_id=<uuid> nur die Chirps dieses Users, mit ?liked_by=<uuid> nur die von diesem User
+// gelikten, mit ?sort=asc|desc die Richtung (Standard asc).
+// Sobald ?limit= oder ?cursor= gesetzt ist, wird paginiert und ein chirpy.ChirpPage zurückgegeben;
+// ohne diese Parameter bleibt es beim bisherigen Array.
+func (cfg *apiConfig) handlerChirpsGet(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	sort := query.Get("sort")
+	if sort == "" {
+		sort = "asc"
+	}
+	if sort != "asc" && sort != "desc" {
+		respondWithError(w, http.StatusBadRequest, "Invalid sort, allowed values are asc and desc", nil)
+		return
+	}
+
+	var authorID uuid.NullUUID
+	if authorIDString := query.Get("author_id"); authorIDString != "" {
+		id, err := uuid.Parse(authorIDString)
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, "Invalid author_id", err)
+			return
+		}
+		authorID = uuid.NullUUID{UUID: id, Valid: true}
+	}
+	var likedBy uuid.NullUUID
+	if likedByString := query.Get("liked_by"); likedByString != "" {
+		id, err := uuid.Parse(likedByString)
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, "Invalid liked_by", err)
+			return
+		}
+		likedBy = uuid.NullUUID{UUID: id, Valid: true}
+	}
+
+	if query.Has("limit") || query.Has("cursor") {
+		cfg.respondWithChirpPage(w, r, authorID, likedBy, sort)
+		return
+	}
+
+	var dbChirps []database.Chirp
+	var err error
+	desc := sort == "desc"
+	switch {
+	case likedBy.Valid && desc:
+		dbChirps, err = cfg.db.GetChirpsLikedByDesc(r.Context(), database.GetChirpsLikedByDescParams{LikedBy: likedBy.UUID, AuthorID: authorID})
+	case likedBy.Valid:
+		dbChirps, err = cfg.db.GetChirpsLikedByAsc(r.Context(), database.GetChirpsLikedByAscParams{LikedBy: likedBy.UUID, AuthorID: authorID})
+	case authorID.Valid && desc:
+		dbChirps, err = cfg.db.GetChirpsByAuthorDesc(r.Context(), authorID.UUID)
+	case authorID.Valid:
+		dbChirps, err = cfg.db.GetChirpsByAuthorAsc(r.Context(), authorID.UUID)
+	case desc:
+		dbChirps, err = cfg.db.GetChirpsDesc(r.Context())
+	default:
+		dbChirps, err = cfg.db.GetChirpsAsc(r.Context())
+	}
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't retrieve chirps", err)
+		return
+	}
+
+	chirps, err := cfg.chirpsJSON(r, dbChirps)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't retrieve chirps", err)
+		return
+	}
+	respondWithJSON(w, http.StatusOK, chirps)
+}
+
+// Beantwortet eine paginierte Listenanfrage per Keyset-Query statt OFFSET.
+func (cfg *apiConfig) respondWithChirpPage(w http.ResponseWriter, r *http.Request, authorID, likedBy uuid.NullUUID, sort string) {
+	query := r.URL.Query()
+	limit, err := parsePageLimit(query.Get("limit"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	cursor, err := pageCursor(query.Get("cursor"), sort)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid cursor", err)
+		return
+	}
+	params := database.GetChirpsPageAscParams{
+		CursorCreatedAt: cursor.CreatedAt,
+		CursorID:        cursor.ID,
+		AuthorID:        authorID,
+		LikedBy:         likedBy,
+		// Einen mehr laden, um zu erkennen, ob es eine nächste Seite gibt.
+		PageLimit: int32(limit + 1),
+	}
+
+	var dbChirps []database.Chirp
+	if sort == "desc" {
+		dbChirps, err = cfg.db.GetChirpsPageDesc(r.Context(), database.GetChirpsPageDescParams(params))
+	} else {
+		dbChirps, err = cfg.db.GetChirpsPageAsc(r.Context(), params)
+	}
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't retrieve chirps", err)
+		return
+	}
+
+	page, err := cfg.chirpPage(r, dbChirps, limit)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't retrieve chirps", err)
+		return
+	}
+	cfg.setNextPageLink(w, r, page)
+	respondWithJSON(w, http.StatusOK, page)
+}
+
+// Baut eine Seite aus bis zu limit+1 geladenen Chirps; der überzählige zeigt an,
+// dass es eine nächste Seite gibt.
+func (cfg *apiConfig) chirpPage(r *http.Request, dbChirps []database.Chirp, limit int) (chirpy.ChirpPage, error) {
+	page := chirpy.ChirpPage{}
+	if len(dbChirps) > limit {
+		dbChirps = dbChirps[:limit]
+		last := dbChirps[len(dbChirps)-1]
+		page.NextCursor = chirpCursor{CreatedAt: last.CreatedAt, ID: last.ID}.encode()
+	}
+	chirps, err := cfg.chirpsJSON(r, dbChirps)
+	if err != nil {
+		return chirpy.ChirpPage{}, err
+	}
+	page.Chirps = chirps
+	return page, nil
+}
+
+// Setzt den Link-Header (rel="next") auf die nächste Seite, mit BASE_URL und BASE_PATH.
+func (cfg *apiConfig) setNextPageLink(w http.ResponseWriter, r *http.Request, page chirpy.ChirpPage) {
+	if page.NextCursor == "" {
+		return
+	}
+	query := r.URL.Query()
+	query.Set("cursor", page.NextCursor)
+	next := cfg.baseURL + cfg.urlPath(r.URL.Path) + "?" + query.Encode()
+	w.Header().Set("Link", "<"+next+`>; rel="next"`)
+}
+
+// Handler für /api/chirps/{chirpID} (GET)
+// Akzeptiert die UUID oder die Short-ID eines Chirps; 400 bei ungültiger ID, 404 wenn es ihn nicht gibt.
+func (cfg *apiConfig) handlerChirpGet(w http.ResponseWriter, r *http.Request) {
+	dbChirp, reqErr := cfg.chirpFromPath(r)
+	if reqErr != nil {
+		respondWithRequestError(w, reqErr)
+		return
+	}
+
+	chirp, err := cfg.chirpJSONFor(r, dbChirp)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't retrieve chirp", err)
+		return
+	}
+	respondWithJSON(w, http.StatusOK, chirp)
+}
+
+// Lädt den Chirp aus dem Pfadparameter {chirpID} (UUID oder Short-ID).
+func (cfg *apiConfig) chirpFromPath(r *http.Request) (database.Chirp, *requestError) {
+	chirpIDString := r.PathValue("chirpID")
+
+	var dbChirp database.Chirp
+	var err error
+	if chirpID, parseErr := uuid.Parse(chirpIDString); parseErr == nil {
+		dbChirp, err = cfg.db.GetChirpByID(r.Context(), chirpID)
+	} else if isShortID(chirpIDString) {
+		dbChirp, err = cfg.db.GetChirpByShortID(r.Context(), chirpIDString)
+	} else {
+		return database.Chirp{}, &requestError{status: http.StatusBadRequest, msg: "Invalid chirp ID", err: parseErr}
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		return database.Chirp{}, &requestError{status: http.StatusNotFound, msg: "Chirp not found"}
+	}
+	if err != nil {
+		return database.Chirp{}, &requestError{status: http.StatusInternalServerError, msg: "Couldn't retrieve chirp", err: err}
+	}
+	return dbChirp, nil
+}
+
+// Wandelt eine Liste von Chirps um; nie nil, damit leere Listen als [] kodiert werden.
+// Like-Zähler und liked_by_me werden für die ganze Liste mit je einer Abfrage geladen.
+func (cfg *apiConfig) chirpsJSON(r *http.Request, dbChirps []database.Chirp) ([]chirpy.Chirp, error) {
+	chirps := make([]chirpy.Chirp, 0, len(dbChirps))
+	if len(dbChirps) == 0 {
+		return chirps, nil
+	}
+	ids := make([]uuid.UUID, 0, len(dbChirps))
+	for _, c := range dbChirps {
+		ids = append(ids, c.ID)
+	}
+
+	counts, err := cfg.db.GetChirpLikeCounts(r.Context(), ids)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't count likes: %w", err)
+	}
+	likeCounts := make(map[uuid.UUID]int64, len(counts))
+	for _, row := range counts {
+		likeCounts[row.ChirpID] = row.LikeCount
+	}
+	// liked_by_me nur mit gültigem Token, sonst fehlt das Feld
+	var likedByMe map[uuid.UUID]bool
+	viewer := cfg.viewerID(r)
+	if viewer.Valid {
+		liked, err := cfg.db.GetChirpsLikedByUser(r.Context(), database.GetChirpsLikedByUserParams{
+			UserID:   viewer.UUID,
+			ChirpIds: ids,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("couldn't load likes: %w", err)
+		}
+		likedByMe = make(map[uuid.UUID]bool, len(liked))
+		for _, id := range liked {
+			likedByMe[id] = true
+		}
+	}
+
+	for _, c := range dbChirps {
+		chirp := cfg.chirpJSONWithEntities(r, c)
+		chirp.LikeCount = likeCounts[c.ID]
+		if likedByMe != nil {
+			liked := likedByMe[c.ID]
+			chirp.LikedByMe = &liked
+		}
+		// Für Admins gilt das Bearbeitungsfenster nicht
+		if until, ok := cfg.editableUntil(c); ok && viewer.Valid && viewer.UUID == c.UserID && !cfg.adminUserIDs[viewer.UUID] {
+			until = until.UTC()
+			chirp.EditableUntil = &until
+		}
+		chirps = append(chirps, chirp)
+	}
+	return chirps, nil
+}
+
+// Wie chirpsJSON für einen einzelnen Chirp.
+func (cfg *apiConfig) chirpJSONFor(r *http.Request, c database.Chirp) (chirpy.Chirp, error) {
+	chirps, err := cfg.chirpsJSON(r, []database.Chirp{c})
+	if err != nil {
+		return chirpy.Chirp{}, err
+	}
+	return chirps[0], nil
+}
+
+// Wie chirpJSON, mit masked_ranges wenn der Request ?include_entities=true setzt.
+func (cfg *apiConfig) chirpJSONWithEntities(r *http.Request, c database.Chirp) chirpy.Chirp {
+	chirp := cfg.chirpJSON(c)
+	if r.URL.Query().Get("include_entities") != "true" || len(c.MaskedRanges) == 0 {
+		return chirp
+	}
+	if err := json.Unmarshal(c.MaskedRanges, &chirp.MaskedRanges); err != nil {
+		log.Printf("Couldn't decode masked ranges of chirp %s: %s", c.ID, err)
+	}
+	return chirp
+}
+
+func (cfg *apiConfig) chirpJSON(c database.Chirp) chirpy.Chirp {
+	return chirpy.Chirp{
+		ID:        c.ID,
+		Body:      c.Body,
+		UserID:    c.UserID,
+		CreatedAt: c.CreatedAt,
+		UpdatedAt: c.UpdatedAt,
+		ShortID:   c.ShortID,
+		URL:       cfg.chirpURL(c.ShortID),
+		Edited:    !c.UpdatedAt.Equal(c.CreatedAt),
+	}
+}
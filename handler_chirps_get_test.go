@@ -0,0 +1,238 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nuke87/go_http_server/internal/database"
+	"github.com/nuke87/go_http_server/pkg/chirpy"
+)
+
+var updateGolden = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+// Vergleicht got mit testdata/name; mit -update wird die Datei neu geschrieben.
+func assertGolden(t *testing.T, name string, got []byte) {
+	t.Helper()
+	var indented bytes.Buffer
+	if err := json.Indent(&indented, got, "", "  "); err != nil {
+		t.Fatalf("response is not JSON: %v\n%s", err, got)
+	}
+	indented.WriteByte('\n')
+	path := filepath.Join("testdata", name)
+	if *updateGolden {
+		if err := os.WriteFile(path, indented.Bytes(), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("%v (run go test -update to create it)", err)
+	}
+	if !bytes.Equal(indented.Bytes(), want) {
+		t.Errorf("response differs from %s (run go test -update if the change is intended)\n got:\n%s\nwant:\n%s", path, indented.Bytes(), want)
+	}
+}
+
+// Legt zwei User und drei Chirps mit festen IDs direkt im Store an.
+func seedChirpList(t *testing.T, ts *testServer) (alice uuid.UUID) {
+	t.Helper()
+	ctx := context.Background()
+	alice = uuid.MustParse("11111111-1111-4111-8111-111111111111")
+	bob := uuid.MustParse("22222222-2222-4222-8222-222222222222")
+	for _, u := range []database.CreateUserParams{
+		{ID: alice, CreatedAt: ts.now, UpdatedAt: ts.now, Email: "alice@example.com", HashedPassword: "x"},
+		{ID: bob, CreatedAt: ts.now, UpdatedAt: ts.now, Email: "bob@example.com", HashedPassword: "x"},
+	} {
+		if _, err := ts.store.CreateUser(ctx, u); err != nil {
+			t.Fatal(err)
+		}
+	}
+	chirps := []database.CreateChirpParams{
+		{ID: uuid.MustParse("aaaaaaaa-0000-4000-8000-000000000001"), UserID: alice, ShortID: "aaaaaaaa",
+			CreatedAt: ts.now, UpdatedAt: ts.now, Body: "first chirp"},
+		{ID: uuid.MustParse("aaaaaaaa-0000-4000-8000-000000000002"), UserID: bob, ShortID: "bbbbbbbb",
+			CreatedAt: ts.now.Add(time.Minute), UpdatedAt: ts.now.Add(2 * time.Minute), Body: "what a ****",
+			MaskedRanges: json.RawMessage(`[{"original":"kerfuffle","replacement":"****","start":7,"end":11}]`)},
+		{ID: uuid.MustParse("aaaaaaaa-0000-4000-8000-000000000003"), UserID: alice, ShortID: "cccccccc",
+			CreatedAt: ts.now.Add(2 * time.Minute), UpdatedAt: ts.now.Add(2 * time.Minute), Body: "line one\nline two"},
+	}
+	for _, c := range chirps {
+		if _, err := ts.store.CreateChirp(ctx, c); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := ts.store.LikeChirp(ctx, database.LikeChirpParams{ChirpID: chirps[1].ID, UserID: alice, CreatedAt: ts.now}); err != nil {
+		t.Fatal(err)
+	}
+	ts.advance(5 * time.Minute)
+	return alice
+}
+
+// Sperrt das Format der Chirp-Liste (pkg/chirpy) gegen versehentliche Änderungen.
+func TestChirpListGolden(t *testing.T) {
+	ts := newTestServer(t)
+	alice := seedChirpList(t, ts)
+	token := ts.token(t, alice)
+
+	rec := ts.do(t, "GET", "/api/chirps?include_entities=true", token, "")
+	expectStatus(t, rec, http.StatusOK)
+	assertGolden(t, "chirp_list.golden.json", rec.Body.Bytes())
+
+	rec = ts.do(t, "GET", "/api/chirps?sort=desc&limit=2", "", "")
+	expectStatus(t, rec, http.StatusOK)
+	assertGolden(t, "chirp_page.golden.json", rec.Body.Bytes())
+}
+
+// Legt Chirps im Minutenabstand an; tokens[i] gehört dem Autor des i-ten Chirps.
+func createChirpsOverTime(t *testing.T, ts *testServer, tokens []string) []chirpy.Chirp {
+	t.Helper()
+	var chirps []chirpy.Chirp
+	for i, token := range tokens {
+		chirps = append(chirps, ts.createChirp(t, token, "chirp "+strconv.Itoa(i)))
+		ts.advance(time.Minute)
+	}
+	return chirps
+}
+
+func chirpIDs(chirps []chirpy.Chirp) []uuid.UUID {
+	ids := make([]uuid.UUID, 0, len(chirps))
+	for _, c := range chirps {
+		ids = append(ids, c.ID)
+	}
+	return ids
+}
+
+func TestChirpsGetSort(t *testing.T) {
+	ts := newTestServer(t)
+	alice, aliceToken := ts.createUser(t, "alice@example.com")
+	_, bobToken := ts.createUser(t, "bob@example.com")
+	c := chirpIDs(createChirpsOverTime(t, ts, []string{aliceToken, bobToken, aliceToken, bobToken, aliceToken}))
+
+	tests := []struct {
+		query string
+		want  []uuid.UUID
+	}{
+		{"", c},
+		{"?sort=asc", c},
+		{"?sort=desc", []uuid.UUID{c[4], c[3], c[2], c[1], c[0]}},
+		{"?author_id=" + alice.ID.String(), []uuid.UUID{c[0], c[2], c[4]}},
+		{"?author_id=" + alice.ID.String() + "&sort=desc", []uuid.UUID{c[4], c[2], c[0]}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			rec := ts.do(t, "GET", "/api/chirps"+tt.query, "", "")
+			expectStatus(t, rec, http.StatusOK)
+			if got := chirpIDs(decodeResponse[[]chirpy.Chirp](t, rec)); !slices.Equal(got, tt.want) {
+				t.Errorf("order = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	for _, bad := range []string{"?sort=newest", "?sort=DESC", "?author_id=nope"} {
+		expectStatus(t, ts.do(t, "GET", "/api/chirps"+bad, "", ""), http.StatusBadRequest)
+	}
+}
+
+// Holt alle Seiten ab path (mit ?limit=) und liefert die Chirps in Reihenfolge.
+func walkChirpPages(t *testing.T, ts *testServer, path string) (chirps []chirpy.Chirp, pages int) {
+	t.Helper()
+	next := path
+	for {
+		rec := ts.do(t, "GET", next, "", "")
+		expectStatus(t, rec, http.StatusOK)
+		page := decodeResponse[chirpy.ChirpPage](t, rec)
+		chirps = append(chirps, page.Chirps...)
+		pages++
+		if page.NextCursor == "" {
+			return chirps, pages
+		}
+		if pages > 20 {
+			t.Fatalf("no last page after %d pages", pages)
+		}
+		next = path + "&cursor=" + page.NextCursor
+	}
+}
+
+func TestChirpsGetPagination(t *testing.T) {
+	ts := newTestServer(t)
+	alice, aliceToken := ts.createUser(t, "alice@example.com")
+	_, bobToken := ts.createUser(t, "bob@example.com")
+
+	// Mehrere Chirps mit gleichem created_at: die ID entscheidet über die Reihenfolge
+	var all []chirpy.Chirp
+	for i, token := range []string{aliceToken, bobToken, aliceToken, aliceToken, bobToken, aliceToken, bobToken} {
+		all = append(all, ts.createChirp(t, token, "chirp "+strconv.Itoa(i)))
+		if i%3 == 2 {
+			ts.advance(time.Minute)
+		}
+	}
+	asc := slices.Clone(all)
+	slices.SortFunc(asc, func(a, b chirpy.Chirp) int {
+		if c := a.CreatedAt.Compare(b.CreatedAt); c != 0 {
+			return c
+		}
+		return bytes.Compare(a.ID[:], b.ID[:])
+	})
+	desc := slices.Clone(asc)
+	slices.Reverse(desc)
+	byAlice := slices.DeleteFunc(slices.Clone(asc), func(c chirpy.Chirp) bool { return c.UserID != alice.ID })
+
+	// alice liked drei Chirps
+	for _, c := range []chirpy.Chirp{asc[0], asc[3], asc[6]} {
+		expectStatus(t, ts.do(t, "POST", "/api/chirps/"+c.ID.String()+"/like", aliceToken, ""), http.StatusOK)
+	}
+	liked := []chirpy.Chirp{asc[0], asc[3], asc[6]}
+
+	tests := []struct {
+		name      string
+		path      string
+		want      []chirpy.Chirp
+		wantPages int
+	}{
+		{"asc", "/api/chirps?limit=3", asc, 3},
+		{"desc", "/api/chirps?sort=desc&limit=3", desc, 3},
+		{"exact multiple of limit", "/api/chirps?limit=7", asc, 1},
+		{"author asc", "/api/chirps?limit=2&author_id=" + alice.ID.String(), byAlice, 2},
+		{"liked by desc", "/api/chirps?sort=desc&limit=2&liked_by=" + alice.ID.String(), []chirpy.Chirp{liked[2], liked[1], liked[0]}, 2},
+		{"default limit", "/api/chirps?sort=asc&limit=", asc, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, pages := walkChirpPages(t, ts, tt.path)
+			if !slices.Equal(chirpIDs(got), chirpIDs(tt.want)) {
+				t.Errorf("order = %v, want %v", chirpIDs(got), chirpIDs(tt.want))
+			}
+			if pages != tt.wantPages {
+				t.Errorf("%d pages, want %d", pages, tt.wantPages)
+			}
+		})
+	}
+
+	for _, bad := range []string{"?limit=0", "?limit=-1", "?limit=ten", "?cursor=not-base64!", "?cursor=" + "Zm9vYmFy"} {
+		expectStatus(t, ts.do(t, "GET", "/api/chirps"+bad, "", ""), http.StatusBadRequest)
+	}
+}
+
+func TestChirpsGetPageLimitCap(t *testing.T) {
+	ts := newTestServer(t)
+	_, token := ts.createUser(t, "alice@example.com")
+	for i := 0; i < maxPageLimit+1; i++ {
+		ts.createChirp(t, token, "chirp "+strconv.Itoa(i))
+	}
+	rec := ts.do(t, "GET", "/api/chirps?limit=1000", "", "")
+	expectStatus(t, rec, http.StatusOK)
+	page := decodeResponse[chirpy.ChirpPage](t, rec)
+	if len(page.Chirps) != maxPageLimit || page.NextCursor == "" {
+		t.Errorf("got %d chirps, next_cursor %q; want %d and a cursor", len(page.Chirps), page.NextCursor, maxPageLimit)
+	}
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/nuke87/go_http_server/internal/database"
+)
+
+const (
+	errCodeEditWindowExpired = "edit_window_expired"
+	defaultChirpEditWindow   = 30 * time.Minute
+)
+
+// Ende des Zeitfensters, in dem der Autor einen Chirp bearbeiten darf. Die Grenze gehört
+// noch dazu: Ein Edit genau zu created_at + CHIRP_EDIT_WINDOW wird angenommen.
+// ok ist false, wenn CHIRP_EDIT_WINDOW=0 das Bearbeiten unbegrenzt erlaubt.
+func (cfg *apiConfig) editableUntil(c database.Chirp) (until time.Time, ok bool) {
+	if cfg.editWindow <= 0 {
+		return time.Time{}, false
+	}
+	return c.CreatedAt.Add(cfg.editWindow), true
+}
+
+// Handler für /api/chirps/{chirpID} (PUT)
+// Ersetzt den Body eines eigenen Chirps: 404 wenn es ihn nicht gibt, 403 für andere User
+// und nach Ablauf des Bearbeitungsfensters (CHIRP_EDIT_WINDOW, Code edit_window_expired).
+// Admins (ADMIN_USER_IDS) dürfen ihre Chirps auch danach bearbeiten.
+// Der neue Body wird wie beim Anlegen geprüft und gefiltert; created_at bleibt unverändert.
+func (cfg *apiConfig) handlerChirpUpdate(w http.ResponseWriter, r *http.Request) {
+	chirp, reqErr := cfg.chirpFromPath(r)
+	if reqErr != nil {
+		respondWithRequestError(w, reqErr)
+		return
+	}
+	if chirp.UserID != userIDFromContext(r.Context()) {
+		respondWithError(w, http.StatusForbidden, "You can't edit this chirp", nil)
+		return
+	}
+	if until, ok := cfg.editableUntil(chirp); ok && cfg.clock.Now().After(until) && !cfg.isAdmin(r) {
+		msg := fmt.Sprintf("The edit window for this chirp ended at %s", until.UTC().Format(time.RFC3339))
+		respondWithErrorCode(w, http.StatusForbidden, errCodeEditWindowExpired, msg, nil)
+		return
+	}
+
+	type requestBody struct {
+		Body string `json:"body"`
+	}
+	var req requestBody
+	if reqErr := decodeJSONBody(w, r, &req); reqErr != nil {
+		respondWithRequestError(w, reqErr)
+		return
+	}
+	if req.Body == "" {
+		respondWithErrorCode(w, http.StatusBadRequest, errCodeMissingField, "body is required", nil)
+		return
+	}
+	body, reqErr := validateChirpBody(req.Body, cfg.bidiPolicy)
+	if reqErr != nil {
+		respondWithRequestError(w, reqErr)
+		return
+	}
+
+	cleanedBody, maskedRanges := cleanProfanity(body, cfg.bannedWords, cfg.stripDiacritics)
+	maskedRangesJSON, err := json.Marshal(maskedRanges)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't encode masked ranges", err)
+		return
+	}
+
+	updated, err := cfg.db.UpdateChirp(r.Context(), database.UpdateChirpParams{
+		ID:           chirp.ID,
+		Body:         cleanedBody,
+		MaskedRanges: maskedRangesJSON,
+		UpdatedAt:    cfg.clock.Now().UTC(),
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't update chirp", err)
+		return
+	}
+	// Gespeicherte Übersetzungen gehören zum alten Text
+	if err := cfg.db.DeleteChirpTranslations(r.Context(), chirp.ID); err != nil {
+		slog.Warn("couldn't delete stale translations", "chirp_id", chirp.ID, "error", err)
+	}
+
+	chirpResp, err := cfg.chirpJSONFor(r, updated)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't retrieve chirp", err)
+		return
+	}
+	respondWithJSON(w, http.StatusOK, chirpResp)
+}
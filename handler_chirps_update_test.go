@@ -0,0 +1,78 @@
+package main
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/nuke87/go_http_server/pkg/chirpy"
+)
+
+func TestChirpUpdateEditWindow(t *testing.T) {
+	tests := []struct {
+		name    string
+		after   time.Duration // Zeit seit dem Anlegen
+		admin   bool
+		window  time.Duration
+		want    int
+		wantErr string
+	}{
+		{name: "inside window", after: time.Minute, window: 30 * time.Minute, want: http.StatusOK},
+		{name: "exactly at the end", after: 30 * time.Minute, window: 30 * time.Minute, want: http.StatusOK},
+		{name: "one nanosecond late", after: 30*time.Minute + time.Nanosecond, window: 30 * time.Minute, want: http.StatusForbidden, wantErr: errCodeEditWindowExpired},
+		{name: "admin after the end", after: 24 * time.Hour, admin: true, window: 30 * time.Minute, want: http.StatusOK},
+		{name: "unlimited window", after: 24 * time.Hour, window: 0, want: http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ts := newTestServer(t, func(cfg *apiConfig) { cfg.editWindow = tt.window })
+			user, token := ts.createUser(t, "author@example.com")
+			if tt.admin {
+				ts.cfg.adminUserIDs[user.ID] = true
+			}
+			chirp := ts.createChirp(t, token, "first version")
+
+			ts.advance(tt.after)
+			rec := ts.do(t, "PUT", "/api/chirps/"+chirp.ID.String(), ts.token(t, user.ID), `{"body":"second version"}`)
+			expectStatus(t, rec, tt.want)
+			if tt.wantErr != "" {
+				if got := decodeResponse[chirpy.ErrorResponse](t, rec).Code; got != tt.wantErr {
+					t.Errorf("code = %q, want %q", got, tt.wantErr)
+				}
+				return
+			}
+			updated := decodeResponse[chirpy.Chirp](t, rec)
+			if updated.Body != "second version" || !updated.Edited {
+				t.Errorf("got body %q, edited %v", updated.Body, updated.Edited)
+			}
+			if !updated.CreatedAt.Equal(chirp.CreatedAt) {
+				t.Errorf("created_at changed from %s to %s", chirp.CreatedAt, updated.CreatedAt)
+			}
+		})
+	}
+}
+
+func TestChirpUpdateOtherUser(t *testing.T) {
+	ts := newTestServer(t)
+	_, authorToken := ts.createUser(t, "author@example.com")
+	_, otherToken := ts.createUser(t, "other@example.com")
+	chirp := ts.createChirp(t, authorToken, "mine")
+
+	rec := ts.do(t, "PUT", "/api/chirps/"+chirp.ID.String(), otherToken, `{"body":"hijacked"}`)
+	expectStatus(t, rec, http.StatusForbidden)
+}
+
+func TestChirpEditableUntil(t *testing.T) {
+	ts := newTestServer(t)
+	_, token := ts.createUser(t, "author@example.com")
+	chirp := ts.createChirp(t, token, "hello")
+
+	if chirp.EditableUntil == nil || !chirp.EditableUntil.Equal(chirp.CreatedAt.Add(defaultChirpEditWindow)) {
+		t.Fatalf("editable_until = %v, want created_at + %s", chirp.EditableUntil, defaultChirpEditWindow)
+	}
+	rec := ts.do(t, "GET", "/api/chirps/"+chirp.ID.String(), "", "")
+	expectStatus(t, rec, http.StatusOK)
+	if got := decodeResponse[chirpy.Chirp](t, rec); got.EditableUntil != nil {
+		t.Errorf("editable_until is shown to anonymous viewers: %v", got.EditableUntil)
+	}
+}
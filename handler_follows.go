@@ -0,0 +1,119 @@
+package main
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/nuke87/go_http_server/internal/database"
+)
+
+// Handler für /api/users/{userID}/follow (POST)
+// Der angemeldete User folgt userID. Erneutes Folgen ändert nichts (204);
+// 400 für sich selbst, 404 wenn es den User nicht gibt, 401 wenn der eigene User gelöscht wurde.
+func (cfg *apiConfig) handlerFollow(w http.ResponseWriter, r *http.Request) {
+	followeeID, reqErr := cfg.followeeFromPath(r)
+	if reqErr != nil {
+		respondWithRequestError(w, reqErr)
+		return
+	}
+
+	err := cfg.db.FollowUser(r.Context(), database.FollowUserParams{
+		FollowerID: userIDFromContext(r.Context()),
+		FolloweeID: followeeID,
+		CreatedAt:  cfg.clock.Now().UTC(),
+	})
+	if isForeignKeyViolation(err, "follows_followee_id_fkey") {
+		// Der User wurde seit der Prüfung gelöscht
+		respondWithError(w, http.StatusNotFound, "User not found", nil)
+		return
+	}
+	if isForeignKeyViolation(err, "follows_follower_id_fkey") {
+		// Gültiges Token, aber der User wurde inzwischen gelöscht
+		respondWithError(w, http.StatusUnauthorized, "User no longer exists", nil)
+		return
+	}
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't follow user", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Handler für /api/users/{userID}/follow (DELETE)
+// Beendet das Folgen; auch ohne vorheriges Folgen 204.
+func (cfg *apiConfig) handlerUnfollow(w http.ResponseWriter, r *http.Request) {
+	followeeID, reqErr := cfg.followeeFromPath(r)
+	if reqErr != nil {
+		respondWithRequestError(w, reqErr)
+		return
+	}
+
+	_, err := cfg.db.UnfollowUser(r.Context(), database.UnfollowUserParams{
+		FollowerID: userIDFromContext(r.Context()),
+		FolloweeID: followeeID,
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't unfollow user", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Liest {userID} und prüft, dass es ein anderer, existierender User ist.
+func (cfg *apiConfig) followeeFromPath(r *http.Request) (uuid.UUID, *requestError) {
+	followeeID, err := uuid.Parse(r.PathValue("userID"))
+	if err != nil {
+		return uuid.Nil, &requestError{status: http.StatusBadRequest, msg: "Invalid user ID", err: err}
+	}
+	if followeeID == userIDFromContext(r.Context()) {
+		return uuid.Nil, &requestError{status: http.StatusBadRequest, msg: "You can't follow yourself"}
+	}
+	_, err = cfg.db.GetUserByID(r.Context(), followeeID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return uuid.Nil, &requestError{status: http.StatusNotFound, msg: "User not found"}
+	}
+	if err != nil {
+		return uuid.Nil, &requestError{status: http.StatusInternalServerError, msg: "Couldn't retrieve user", err: err}
+	}
+	return followeeID, nil
+}
+
+// Handler für /api/feed (GET)
+// Chirps der Users, denen der angemeldete User folgt, neueste zuerst. Immer paginiert
+// wie GET /api/chirps mit ?limit= und ?cursor=, Antwort ist ein chirpy.ChirpPage.
+func (cfg *apiConfig) handlerFeed(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	limit, err := parsePageLimit(query.Get("limit"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	cursor, err := pageCursor(query.Get("cursor"), "desc")
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid cursor", err)
+		return
+	}
+
+	dbChirps, err := cfg.db.GetFeedPage(r.Context(), database.GetFeedPageParams{
+		FollowerID:      userIDFromContext(r.Context()),
+		CursorCreatedAt: cursor.CreatedAt,
+		CursorID:        cursor.ID,
+		PageLimit:       int32(limit + 1),
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't retrieve feed", err)
+		return
+	}
+	page, err := cfg.chirpPage(r, dbChirps, limit)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't retrieve feed", err)
+		return
+	}
+	cfg.setNextPageLink(w, r, page)
+	respondWithJSON(w, http.StatusOK, page)
+}
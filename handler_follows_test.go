@@ -0,0 +1,97 @@
+package main
+
+import (
+	"bytes"
+	"net/http"
+	"slices"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nuke87/go_http_server/pkg/chirpy"
+)
+
+func TestFollow(t *testing.T) {
+	ts := newTestServer(t)
+	alice, aliceToken := ts.createUser(t, "alice@example.com")
+	bob, _ := ts.createUser(t, "bob@example.com")
+	path := "/api/users/" + bob.ID.String() + "/follow"
+
+	// Folgen und Entfolgen sind idempotent
+	for i := 0; i < 2; i++ {
+		if rec := ts.do(t, "POST", path, aliceToken, ""); rec.Code != http.StatusNoContent {
+			t.Fatalf("follow #%d: status %d, body %s", i+1, rec.Code, rec.Body)
+		}
+	}
+	for i := 0; i < 2; i++ {
+		if rec := ts.do(t, "DELETE", path, aliceToken, ""); rec.Code != http.StatusNoContent {
+			t.Fatalf("unfollow #%d: status %d, body %s", i+1, rec.Code, rec.Body)
+		}
+	}
+
+	expectStatus(t, ts.do(t, "POST", "/api/users/"+alice.ID.String()+"/follow", aliceToken, ""), http.StatusBadRequest)
+	expectStatus(t, ts.do(t, "POST", "/api/users/"+uuid.NewString()+"/follow", aliceToken, ""), http.StatusNotFound)
+	expectStatus(t, ts.do(t, "POST", "/api/users/nope/follow", aliceToken, ""), http.StatusBadRequest)
+	expectStatus(t, ts.do(t, "POST", path, "", ""), http.StatusUnauthorized)
+}
+
+func TestFeedPagination(t *testing.T) {
+	ts := newTestServer(t)
+	_, aliceToken := ts.createUser(t, "alice@example.com")
+	bob, bobToken := ts.createUser(t, "bob@example.com")
+	carol, carolToken := ts.createUser(t, "carol@example.com")
+	_, daveToken := ts.createUser(t, "dave@example.com")
+
+	var followed []chirpy.Chirp
+	for i, token := range []string{bobToken, carolToken, daveToken, bobToken, aliceToken, carolToken, bobToken} {
+		chirp := ts.createChirp(t, token, "chirp "+strconv.Itoa(i))
+		if token == bobToken || token == carolToken {
+			followed = append(followed, chirp)
+		}
+		if i%2 == 1 { // Paare mit gleichem created_at
+			ts.advance(time.Minute)
+		}
+	}
+	slices.SortFunc(followed, func(a, b chirpy.Chirp) int {
+		if c := b.CreatedAt.Compare(a.CreatedAt); c != 0 {
+			return c
+		}
+		return bytes.Compare(b.ID[:], a.ID[:])
+	})
+
+	// Ohne Follows ist der Feed leer
+	rec := ts.do(t, "GET", "/api/feed", aliceToken, "")
+	expectStatus(t, rec, http.StatusOK)
+	if page := decodeResponse[chirpy.ChirpPage](t, rec); len(page.Chirps) != 0 || page.NextCursor != "" {
+		t.Errorf("feed without follows = %+v", page)
+	}
+
+	for _, id := range []uuid.UUID{bob.ID, carol.ID} {
+		if rec := ts.do(t, "POST", "/api/users/"+id.String()+"/follow", aliceToken, ""); rec.Code != http.StatusNoContent {
+			t.Fatalf("follow: status %d", rec.Code)
+		}
+	}
+
+	var got []chirpy.Chirp
+	next := "/api/feed?limit=2"
+	for pages := 0; next != ""; pages++ {
+		if pages > 10 {
+			t.Fatal("no last page")
+		}
+		rec := ts.do(t, "GET", next, aliceToken, "")
+		expectStatus(t, rec, http.StatusOK)
+		page := decodeResponse[chirpy.ChirpPage](t, rec)
+		got = append(got, page.Chirps...)
+		next = ""
+		if page.NextCursor != "" {
+			next = "/api/feed?limit=2&cursor=" + page.NextCursor
+		}
+	}
+	if !slices.Equal(chirpIDs(got), chirpIDs(followed)) {
+		t.Errorf("feed = %v, want newest first %v", chirpIDs(got), chirpIDs(followed))
+	}
+
+	expectStatus(t, ts.do(t, "GET", "/api/feed?cursor=bad!", aliceToken, ""), http.StatusBadRequest)
+	expectStatus(t, ts.do(t, "GET", "/api/feed", "", ""), http.StatusUnauthorized)
+}
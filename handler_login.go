@@ -0,0 +1,89 @@
+package main
+
+import (
+	"database/sql"
+	"errors"
+	"math"
+	"net/http"
+	"time"
+
+	"github.com/nuke87/go_http_server/internal/auth"
+	"github.com/nuke87/go_http_server/internal/database"
+	"github.com/nuke87/go_http_server/pkg/chirpy"
+)
+
+// Handler für /api/login (POST)
+// Erwartet JSON {"email": "...", "password": "..."} und gibt bei Erfolg den User samt Access-Token zurück.
+func (cfg *apiConfig) handlerLogin(w http.ResponseWriter, r *http.Request) {
+	type parameters struct {
+		Email            string     `json:"email"`
+		Password         string     `json:"password"`
+		ExpiresInSeconds jsonNumber `json:"expires_in_seconds"` // Optional, höchstens JWT_EXPIRES_IN
+	}
+	var params parameters
+	if reqErr := decodeJSONBody(w, r, &params); reqErr != nil {
+		respondWithRequestError(w, reqErr)
+		return
+	}
+
+	expiresInSeconds, reqErr := params.ExpiresInSeconds.Int("expires_in_seconds", 0, math.MaxInt32)
+	if reqErr != nil {
+		respondWithRequestError(w, reqErr)
+		return
+	}
+
+	// Wie bei der Registrierung normalisieren; ungültige Adressen finden einfach keinen User.
+	email := params.Email
+	if normalized, reqErr := normalizeEmail(email); reqErr == nil {
+		email = normalized
+	}
+
+	// Unbekannte E-Mail und falsches Passwort bekommen dieselbe Antwort.
+	user, err := cfg.db.GetUserByEmail(r.Context(), email)
+	if errors.Is(err, sql.ErrNoRows) {
+		auth.CheckDummyPassword(params.Password) // Gleiche Laufzeit wie ein falsches Passwort
+		respondWithError(w, http.StatusUnauthorized, "Incorrect email or password", nil)
+		return
+	}
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't look up user", err)
+		return
+	}
+	if err := auth.CheckPasswordHash(params.Password, user.HashedPassword); err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Incorrect email or password", nil)
+		return
+	}
+
+	expiresIn := cfg.jwtExpiresIn
+	if expiresInSeconds > 0 && time.Duration(expiresInSeconds)*time.Second < expiresIn {
+		expiresIn = time.Duration(expiresInSeconds) * time.Second
+	}
+	token, err := auth.MakeJWT(user.ID, cfg.jwtSecret, cfg.clock.Now(), expiresIn)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't create access token", err)
+		return
+	}
+
+	refreshToken, err := auth.MakeRefreshToken()
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't create refresh token", err)
+		return
+	}
+	now := cfg.clock.Now().UTC()
+	_, err = cfg.db.CreateRefreshToken(r.Context(), database.CreateRefreshTokenParams{
+		Token:     refreshToken,
+		CreatedAt: now,
+		UserID:    user.ID,
+		ExpiresAt: now.Add(refreshTokenLifetime),
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't save refresh token", err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, chirpy.LoginResponse{
+		User:         userJSON(user),
+		Token:        token,
+		RefreshToken: refreshToken,
+	})
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"crypto/subtle"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/nuke87/go_http_server/internal/auth"
+	"github.com/nuke87/go_http_server/internal/database"
+)
+
+// Event, das ein Upgrade auf Chirpy Red meldet
+const polkaEventUserUpgraded = "user.upgraded"
+
+// Handler für /api/polka/webhooks (POST)
+// Erwartet {"event": "...", "data": {"user_id": "..."}} und "Authorization: ApiKey <POLKA_KEY>".
+// Bei "user.upgraded" wird der User auf Chirpy Red gesetzt, alle anderen Events werden nur quittiert.
+func (cfg *apiConfig) handlerPolkaWebhook(w http.ResponseWriter, r *http.Request) {
+	apiKey, err := auth.GetAPIKey(r.Header)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Couldn't find api key", err)
+		return
+	}
+	// Ohne POLKA_KEY wird jeder Webhook abgelehnt.
+	if cfg.polkaKey == "" || subtle.ConstantTimeCompare([]byte(apiKey), []byte(cfg.polkaKey)) != 1 {
+		respondWithError(w, http.StatusUnauthorized, "Invalid api key", nil)
+		return
+	}
+
+	type parameters struct {
+		Event string `json:"event"`
+		Data  struct {
+			UserID uuid.UUID `json:"user_id"`
+		} `json:"data"`
+	}
+	params := parameters{}
+	if reqErr := decodeJSONBody(w, r, &params); reqErr != nil {
+		respondWithRequestError(w, reqErr)
+		return
+	}
+
+	if params.Event != polkaEventUserUpgraded {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	if params.Data.UserID == uuid.Nil {
+		respondWithErrorCode(w, http.StatusBadRequest, errCodeMissingField, "data.user_id is required", nil)
+		return
+	}
+
+	rows, err := cfg.db.UpgradeUserToChirpyRed(r.Context(), database.UpgradeUserToChirpyRedParams{
+		ID:        params.Data.UserID,
+		UpdatedAt: cfg.clock.Now().UTC(),
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't upgrade user", err)
+		return
+	}
+	if rows == 0 {
+		respondWithError(w, http.StatusNotFound, "Couldn't find user", nil)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
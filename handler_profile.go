@@ -0,0 +1,125 @@
+package main
+
+import (
+	"database/sql"
+	"errors"
+	"html/template"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/nuke87/go_http_server/internal/database"
+)
+
+const (
+	maxDisplayNameLength = 50
+	maxBioLength         = 160
+)
+
+// Anfrage für PUT /api/users/me/profile; fehlende Felder werden geleert.
+type profileRequest struct {
+	DisplayName string `json:"display_name"`
+	Bio         string `json:"bio"`
+}
+
+// Prüft und bereinigt ein Profil wie einen Chirp: Text.Validate, danach der Profanity-Filter.
+// Der Anzeigename ist einzeilig und ohne Bidi-Steuerzeichen, die Bio folgt CHIRP_BIDI_CONTROLS.
+// Beide Felder dürfen leer sein.
+func (cfg *apiConfig) cleanProfile(req profileRequest) (profileRequest, *requestError) {
+	displayName, reqErr := Text(strings.TrimSpace(req.DisplayName)).Validate(0, maxDisplayNameLength, textPolicy{Bidi: bidiReject})
+	if reqErr != nil {
+		return req, reqErr.forField("display_name")
+	}
+	bio, reqErr := Text(strings.TrimSpace(req.Bio)).Validate(0, maxBioLength, textPolicy{Multiline: true, Bidi: cfg.bidiPolicy})
+	if reqErr != nil {
+		return req, reqErr.forField("bio")
+	}
+
+	req.DisplayName, _ = cleanProfanity(string(displayName), cfg.bannedWords, cfg.stripDiacritics)
+	req.Bio, _ = cleanProfanity(string(bio), cfg.bannedWords, cfg.stripDiacritics)
+	return req, nil
+}
+
+// Handler für /api/users/me/profile (PUT)
+// Erwartet {"display_name": "...", "bio": "..."} und speichert die bereinigten Werte.
+func (cfg *apiConfig) handlerProfileUpdate(w http.ResponseWriter, r *http.Request) {
+	var req profileRequest
+	if reqErr := decodeJSONBody(w, r, &req); reqErr != nil {
+		respondWithRequestError(w, reqErr)
+		return
+	}
+	req, reqErr := cfg.cleanProfile(req)
+	if reqErr != nil {
+		respondWithRequestError(w, reqErr)
+		return
+	}
+
+	user, err := cfg.db.UpdateUserProfile(r.Context(), database.UpdateUserProfileParams{
+		ID:          userIDFromContext(r.Context()),
+		DisplayName: req.DisplayName,
+		Bio:         req.Bio,
+		UpdatedAt:   cfg.clock.Now().UTC(),
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		// Gültiges Token, aber der User wurde inzwischen gelöscht
+		respondWithError(w, http.StatusUnauthorized, "User no longer exists", nil)
+		return
+	}
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't update profile", err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, userJSON(user))
+}
+
+// Öffentliche Profilseite; Bio kommt bereits escaped aus linkify.
+var profilePageTemplate = template.Must(template.New("profile").Parse(`<!DOCTYPE html>
+<html>
+
+<head>
+	<meta charset="utf-8">
+	<title>{{.Name}} on Chirpy</title>
+</head>
+
+<body>
+	<h1>{{.Name}}</h1>
+	{{if .Bio}}<p>{{.Bio}}</p>{{end}}
+</body>
+
+</html>
+`))
+
+// Handler für /users/{userID} (GET)
+// Zeigt das öffentliche Profil als HTML: Anzeigename (sonst "Chirpy user") und die verlinkte Bio.
+func (cfg *apiConfig) handlerProfilePage(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuid.Parse(r.PathValue("userID"))
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusBadRequest)
+		return
+	}
+	user, err := cfg.db.GetUserByID(r.Context(), userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		http.Error(w, "User not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		log.Printf("Couldn't retrieve user %s: %s", userID, err)
+		http.Error(w, "Couldn't retrieve user", http.StatusInternalServerError)
+		return
+	}
+
+	name := user.DisplayName
+	if name == "" {
+		name = "Chirpy user"
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	err = profilePageTemplate.Execute(w, struct {
+		Name string
+		Bio  template.HTML
+	}{Name: name, Bio: template.HTML(linkify(user.Bio))})
+	if err != nil {
+		log.Printf("Couldn't render profile page: %s", err)
+	}
+}
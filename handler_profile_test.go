@@ -0,0 +1,131 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/nuke87/go_http_server/pkg/chirpy"
+)
+
+func profileBody(displayName, bio string) string {
+	payload, _ := json.Marshal(profileRequest{DisplayName: displayName, Bio: bio})
+	return string(payload)
+}
+
+func TestProfileUpdateCleansBio(t *testing.T) {
+	ts := newTestServer(t)
+	user, token := ts.createUser(t, "alice@example.com")
+
+	bio := "I love kerfuffle and SHARBERT\nhttps://example.com/me <script>alert(1)</script>"
+	rec := ts.do(t, "PUT", "/api/users/me/profile", token, profileBody("  Alice  ", bio))
+	expectStatus(t, rec, http.StatusOK)
+	got := decodeResponse[chirpy.User](t, rec)
+
+	if got.DisplayName != "Alice" {
+		t.Errorf("display_name = %q, want trimmed %q", got.DisplayName, "Alice")
+	}
+	wantBio := "I love **** and ****\nhttps://example.com/me <script>alert(1)</script>"
+	if got.Bio != wantBio {
+		t.Errorf("bio = %q, want %q", got.Bio, wantBio)
+	}
+	wantHTML := "I love **** and ****<br>\n" +
+		`<a href="https://example.com/me" rel="nofollow noopener ugc">https://example.com/me</a> ` +
+		"&lt;script&gt;alert(1)&lt;/script&gt;"
+	if got.BioHTML != wantHTML {
+		t.Errorf("bio_html = %q, want %q", got.BioHTML, wantHTML)
+	}
+
+	// Die öffentliche JSON-Ansicht liefert dieselben bereinigten Werte
+	rec = ts.do(t, "GET", "/api/users/"+user.ID.String(), "", "")
+	expectStatus(t, rec, http.StatusOK)
+	if public := decodeResponse[chirpy.User](t, rec); public.Bio != wantBio || public.BioHTML != wantHTML {
+		t.Errorf("public profile = %+v", public)
+	}
+}
+
+func TestProfilePage(t *testing.T) {
+	ts := newTestServer(t)
+	user, token := ts.createUser(t, "alice@example.com")
+	rec := ts.do(t, "PUT", "/api/users/me/profile", token,
+		profileBody("<i>Alice</i>", "fornax https://example.com <script>alert(1)</script>"))
+	expectStatus(t, rec, http.StatusOK)
+
+	rec = ts.do(t, "GET", "/users/"+user.ID.String(), "", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, body %s", rec.Code, rec.Body)
+	}
+	if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/html") {
+		t.Errorf("Content-Type = %q", ct)
+	}
+	page := rec.Body.String()
+	for _, want := range []string{
+		"<h1>&lt;i&gt;Alice&lt;/i&gt;</h1>",
+		`<a href="https://example.com" rel="nofollow noopener ugc">https://example.com</a>`,
+		"&lt;script&gt;alert(1)&lt;/script&gt;",
+		"****",
+	} {
+		if !strings.Contains(page, want) {
+			t.Errorf("page does not contain %q:\n%s", want, page)
+		}
+	}
+	for _, bad := range []string{"<script>", "<i>", "fornax"} {
+		if strings.Contains(page, bad) {
+			t.Errorf("page contains %q:\n%s", bad, page)
+		}
+	}
+}
+
+func TestProfilePageNotFound(t *testing.T) {
+	ts := newTestServer(t)
+	rec := ts.do(t, "GET", "/users/00000000-0000-0000-0000-000000000001", "", "")
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("status = %d, want 404", rec.Code)
+	}
+	rec = ts.do(t, "GET", "/users/not-a-uuid", "", "")
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want 400", rec.Code)
+	}
+}
+
+func TestProfileUpdateValidation(t *testing.T) {
+	tests := []struct {
+		name      string
+		body      string
+		wantField string
+		wantCode  string
+	}{
+		{"display name too long", profileBody(strings.Repeat("ä", maxDisplayNameLength+1), ""), "display_name", errCodeTooLong},
+		{"display name with newline", profileBody("Al\nice", ""), "display_name", errCodeInvalidCharacters},
+		{"display name with bidi control", profileBody("Al\u202eice", ""), "display_name", errCodeInvalidCharacters},
+		{"bio too long", profileBody("", strings.Repeat("x", maxBioLength+1)), "bio", errCodeTooLong},
+		{"bio with control character", profileBody("", "bell\a"), "bio", errCodeInvalidCharacters},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ts := newTestServer(t)
+			_, token := ts.createUser(t, "alice@example.com")
+			rec := ts.do(t, "PUT", "/api/users/me/profile", token, tt.body)
+			expectStatus(t, rec, http.StatusBadRequest)
+			got := decodeResponse[chirpy.ErrorResponse](t, rec)
+			if got.Field != tt.wantField || got.Code != tt.wantCode {
+				t.Errorf("field/code = %q/%q, want %q/%q", got.Field, got.Code, tt.wantField, tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestProfileUpdateDeletedUser(t *testing.T) {
+	ts := newTestServer(t)
+	user, token := ts.createUser(t, "alice@example.com")
+	if _, err := ts.store.DeleteUser(context.Background(), user.ID); err != nil {
+		t.Fatal(err)
+	}
+	rec := ts.do(t, "PUT", "/api/users/me/profile", token, profileBody("Alice", ""))
+	expectStatus(t, rec, http.StatusUnauthorized)
+	if got := decodeResponse[chirpy.ErrorResponse](t, rec); got.Error != "User no longer exists" {
+		t.Errorf("error = %q, want the same message as the other user-scoped endpoints", got.Error)
+	}
+}
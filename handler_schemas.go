@@ -0,0 +1,24 @@
+package main
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/nuke87/go_http_server/pkg/chirpy"
+)
+
+// Handler für /api/schemas/{name}.json (GET)
+// Liefert das JSON-Schema eines Antworttyps, damit Clients Antworten selbst prüfen können.
+func handlerSchemaGet(w http.ResponseWriter, r *http.Request) {
+	name, ok := strings.CutSuffix(r.PathValue("file"), ".json")
+	if !ok {
+		respondWithError(w, http.StatusNotFound, "Schema not found", nil)
+		return
+	}
+	schema, ok := chirpy.Schemas()[name]
+	if !ok {
+		respondWithError(w, http.StatusNotFound, "Schema not found", nil)
+		return
+	}
+	respondWithJSON(w, http.StatusOK, schema)
+}
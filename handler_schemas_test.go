@@ -0,0 +1,208 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"slices"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nuke87/go_http_server/pkg/chirpy"
+)
+
+// Prüft v gegen den Teil von JSON Schema, den chirpy.Schemas erzeugt: type, format,
+// properties, required, additionalProperties und items. Liefert alle Abweichungen.
+func schemaViolations(schema map[string]any, v any, path string) []string {
+	var problems []string
+	fail := func(format string, args ...any) {
+		problems = append(problems, path+": "+fmt.Sprintf(format, args...))
+	}
+
+	if want, ok := schema["type"]; ok {
+		var types []string
+		switch want := want.(type) {
+		case string:
+			types = []string{want}
+		case []any:
+			for _, t := range want {
+				types = append(types, t.(string))
+			}
+		}
+		if !slices.ContainsFunc(types, func(t string) bool { return jsonTypeIs(v, t) }) {
+			fail("got %s, want type %v", jsonValueType(v), types)
+			return problems
+		}
+	}
+
+	switch v := v.(type) {
+	case string:
+		switch schema["format"] {
+		case "date-time":
+			if _, err := time.Parse(time.RFC3339Nano, v); err != nil {
+				fail("%q is not a date-time", v)
+			}
+		case "uuid":
+			if _, err := uuid.Parse(v); err != nil {
+				fail("%q is not a uuid", v)
+			}
+		}
+	case []any:
+		if items, ok := schema["items"].(map[string]any); ok {
+			for i, item := range v {
+				problems = append(problems, schemaViolations(items, item, fmt.Sprintf("%s[%d]", path, i))...)
+			}
+		}
+	case map[string]any:
+		properties, _ := schema["properties"].(map[string]any)
+		required, _ := schema["required"].([]any)
+		for _, name := range required {
+			if _, ok := v[name.(string)]; !ok {
+				fail("required key %q missing", name)
+			}
+		}
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			if prop, ok := properties[k].(map[string]any); ok {
+				problems = append(problems, schemaViolations(prop, v[k], path+"."+k)...)
+				continue
+			}
+			switch extra := schema["additionalProperties"].(type) {
+			case bool:
+				if !extra {
+					fail("key %q is not in the schema", k)
+				}
+			case map[string]any:
+				problems = append(problems, schemaViolations(extra, v[k], path+"."+k)...)
+			}
+		}
+	}
+	return problems
+}
+
+func jsonTypeIs(v any, t string) bool {
+	if t == "integer" {
+		f, ok := v.(float64)
+		return ok && f == math.Trunc(f)
+	}
+	return jsonValueType(v) == t
+}
+
+func jsonValueType(v any) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case float64:
+		return "number"
+	case string:
+		return "string"
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	}
+	return fmt.Sprintf("%T", v)
+}
+
+// Lädt ein Schema über GET /api/schemas/{name}.json.
+func fetchSchema(t *testing.T, ts *testServer, name string) map[string]any {
+	t.Helper()
+	rec := ts.do(t, "GET", "/api/schemas/"+name+".json", "", "")
+	expectStatus(t, rec, http.StatusOK)
+	return decodeResponse[map[string]any](t, rec)
+}
+
+// Die Antworten der echten Handler entsprechen den veröffentlichten Schemas.
+func TestResponsesMatchSchemas(t *testing.T) {
+	ts := newTestServer(t, func(cfg *apiConfig) { cfg.editWindow = time.Hour })
+	alice, token := ts.createUser(t, "alice@example.com")
+	chirp := ts.createChirp(t, token, "what a kerfuffle")
+	expectStatus(t, ts.do(t, "POST", "/api/chirps/"+chirp.ID.String()+"/like", token, ""), http.StatusOK)
+	ts.createChirp(t, token, "second")
+	rec := ts.do(t, "POST", "/api/users/me/templates", token, `{"name":"greet","body":"hi {{name}}"}`)
+	expectStatus(t, rec, http.StatusCreated)
+	template := decodeResponse[chirpy.ChirpTemplate](t, rec)
+
+	tests := []struct {
+		name   string
+		schema string
+		method string
+		path   string
+		token  string
+		body   string
+	}{
+		{"create user", "user", "POST", "/api/users", "", `{"email":"bob@example.com","password":"hunter22"}`},
+		{"get user", "user", "GET", "/api/users/" + alice.ID.String(), "", ""},
+		{"update profile", "user", "PUT", "/api/users/me/profile", token, profileBody("Alice", "see https://example.com")},
+		{"login", "login", "POST", "/api/login", "", `{"email":"alice@example.com","password":"hunter22"}`},
+		{"create chirp", "chirp", "POST", "/api/chirps", token, `{"body":"hello"}`},
+		{"create chirp from template", "chirp", "POST", "/api/chirps", token, `{"template_id":"` + template.ID.String() + `","variables":{}}`},
+		{"get chirp", "chirp", "GET", "/api/chirps/" + chirp.ID.String() + "?include_entities=true", token, ""},
+		{"list chirps", "chirp_list", "GET", "/api/chirps?include_entities=true", token, ""},
+		{"empty list", "chirp_list", "GET", "/api/chirps?author_id=" + uuid.NewString(), "", ""},
+		{"chirp page", "chirp_page", "GET", "/api/chirps?limit=1", token, ""},
+		{"last chirp page", "chirp_page", "GET", "/api/chirps?limit=10", "", ""},
+		{"empty feed", "chirp_page", "GET", "/api/feed", token, ""},
+		{"create template", "chirp_template", "POST", "/api/users/me/templates", token, `{"name":"other","body":"x"}`},
+		{"validation error", "error", "POST", "/api/chirps", token, `{"body":""}`},
+		{"auth error", "error", "POST", "/api/chirps", "", `{"body":"hello"}`},
+		{"not found", "error", "GET", "/api/chirps/" + uuid.NewString(), "", ""},
+		{"decode error", "error", "POST", "/api/users", "", `{"email":`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schema := fetchSchema(t, ts, tt.schema)
+			rec := ts.do(t, tt.method, tt.path, tt.token, tt.body)
+			if (tt.schema == "error") != (rec.Code >= 400) {
+				t.Fatalf("status %d, body %s", rec.Code, rec.Body)
+			}
+			var got any
+			if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+				t.Fatalf("response is not JSON: %v", err)
+			}
+			for _, p := range schemaViolations(schema, got, tt.schema) {
+				t.Error(p)
+			}
+		})
+	}
+}
+
+// Ohne diese Probe würde ein zu nachsichtiger Validator jede Abweichung durchlassen.
+func TestSchemaViolationsDetectsDrift(t *testing.T) {
+	ts := newTestServer(t)
+	schema := fetchSchema(t, ts, "chirp")
+	_, token := ts.createUser(t, "alice@example.com")
+	rec := ts.do(t, "POST", "/api/chirps", token, `{"body":"hello"}`)
+	expectStatus(t, rec, http.StatusCreated)
+
+	tests := []struct {
+		name   string
+		mutate func(map[string]any)
+		want   string
+	}{
+		{"unknown key", func(c map[string]any) { c["author"] = "alice" }, `key "author" is not in the schema`},
+		{"missing key", func(c map[string]any) { delete(c, "short_id") }, `required key "short_id" missing`},
+		{"wrong type", func(c map[string]any) { c["like_count"] = "1" }, "want type [integer]"},
+		{"bad format", func(c map[string]any) { c["user_id"] = "nope" }, "is not a uuid"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := decodeResponse[map[string]any](t, rec)
+			tt.mutate(got)
+			problems := schemaViolations(schema, got, "chirp")
+			if !slices.ContainsFunc(problems, func(p string) bool { return strings.Contains(p, tt.want) }) {
+				t.Errorf("violations = %q, want one containing %q", problems, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,245 @@
+package main
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/nuke87/go_http_server/internal/database"
+	"github.com/nuke87/go_http_server/pkg/chirpy"
+)
+
+const (
+	maxTemplatesPerUser   = 20
+	maxTemplateNameLength = 64
+)
+
+// Platzhalter der Form {{name}}, Leerzeichen innerhalb der Klammern sind erlaubt.
+var templatePlaceholder = regexp.MustCompile(`\{\{\s*([A-Za-z0-9_]+)\s*\}\}`)
+
+// Setzt die Variablen in den Body einer Vorlage ein. Der Text bleibt Klartext, es wird
+// nichts escaped. Unbekannte Platzhalter werden leer ersetzt und als Warnung gemeldet.
+func renderTemplate(body string, variables map[string]string) (string, []string) {
+	var warnings []string
+	seen := map[string]bool{}
+	rendered := templatePlaceholder.ReplaceAllStringFunc(body, func(placeholder string) string {
+		name := templatePlaceholder.FindStringSubmatch(placeholder)[1]
+		value, ok := variables[name]
+		if !ok && !seen[name] {
+			seen[name] = true
+			warnings = append(warnings, fmt.Sprintf("unknown template variable %q rendered as empty string", name))
+		}
+		return value
+	})
+	return rendered, warnings
+}
+
+// Anfrage zum Anlegen oder Ersetzen einer Vorlage
+type templateRequest struct {
+	Name string `json:"name"`
+	Body string `json:"body"`
+}
+
+// Prüft Name und Body einer Vorlage. Der Body durchläuft dieselbe Prüfung wie ein Chirp,
+// der Profanity-Filter greift erst beim Veröffentlichen.
+func (cfg *apiConfig) validateTemplateRequest(req templateRequest) (templateRequest, *requestError) {
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" {
+		return req, &requestError{status: http.StatusBadRequest, code: errCodeMissingField, field: "name", msg: "name is required"}
+	}
+	// Einzeilig, ohne Bidi-Steuerzeichen; NFC, damit die Eindeutigkeit pro User greift
+	name, reqErr := Text(req.Name).Validate(1, maxTemplateNameLength, textPolicy{Bidi: bidiReject})
+	if reqErr != nil {
+		return req, reqErr.forField("name")
+	}
+	req.Name = string(name)
+	if req.Body == "" {
+		return req, &requestError{status: http.StatusBadRequest, code: errCodeMissingField, field: "body", msg: "body is required"}
+	}
+	body, reqErr := validateChirpBody(req.Body, cfg.bidiPolicy)
+	if reqErr != nil {
+		return req, reqErr
+	}
+	req.Body = body
+	return req, nil
+}
+
+// Handler für /api/users/me/templates (GET)
+// Gibt alle Vorlagen des angemeldeten Users zurück, älteste zuerst.
+func (cfg *apiConfig) handlerTemplatesList(w http.ResponseWriter, r *http.Request) {
+	dbTemplates, err := cfg.db.GetChirpTemplatesByUser(r.Context(), userIDFromContext(r.Context()))
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't retrieve templates", err)
+		return
+	}
+
+	templates := make([]chirpy.ChirpTemplate, 0, len(dbTemplates))
+	for _, t := range dbTemplates {
+		templates = append(templates, templateJSON(t))
+	}
+	respondWithJSON(w, http.StatusOK, templates)
+}
+
+// Handler für /api/users/me/templates (POST)
+// Erwartet {"name": "...", "body": "..."}; höchstens maxTemplatesPerUser Vorlagen pro User.
+func (cfg *apiConfig) handlerTemplateCreate(w http.ResponseWriter, r *http.Request) {
+	var req templateRequest
+	if reqErr := decodeJSONBody(w, r, &req); reqErr != nil {
+		respondWithRequestError(w, reqErr)
+		return
+	}
+	req, reqErr := cfg.validateTemplateRequest(req)
+	if reqErr != nil {
+		respondWithRequestError(w, reqErr)
+		return
+	}
+
+	now := cfg.clock.Now().UTC()
+	template, err := cfg.db.CreateChirpTemplateWithinQuota(r.Context(), database.CreateChirpTemplateParams{
+		ID:        uuid.New(),
+		CreatedAt: now,
+		UpdatedAt: now,
+		UserID:    userIDFromContext(r.Context()),
+		Name:      req.Name,
+		Body:      req.Body,
+	}, maxTemplatesPerUser)
+	if errors.Is(err, errTemplateQuotaExceeded) {
+		respondWithError(w, http.StatusConflict, fmt.Sprintf("You can have at most %d templates", maxTemplatesPerUser), nil)
+		return
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		// Gültiges Token, aber der User wurde inzwischen gelöscht
+		respondWithError(w, http.StatusUnauthorized, "User no longer exists", nil)
+		return
+	}
+	if isUniqueViolation(err, "chirp_templates_user_id_name_key") {
+		respondWithError(w, http.StatusConflict, "A template with this name already exists", nil)
+		return
+	}
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't create template", err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, templateJSON(template))
+}
+
+// Handler für /api/users/me/templates/{templateID} (GET)
+func (cfg *apiConfig) handlerTemplateGet(w http.ResponseWriter, r *http.Request) {
+	templateID, reqErr := templateIDFromPath(r)
+	if reqErr != nil {
+		respondWithRequestError(w, reqErr)
+		return
+	}
+	template, reqErr := cfg.templateForUser(r, templateID)
+	if reqErr != nil {
+		respondWithRequestError(w, reqErr)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, templateJSON(template))
+}
+
+// Handler für /api/users/me/templates/{templateID} (PUT)
+// Ersetzt Name und Body der Vorlage; 404, wenn sie nicht dem User gehört.
+func (cfg *apiConfig) handlerTemplateUpdate(w http.ResponseWriter, r *http.Request) {
+	templateID, reqErr := templateIDFromPath(r)
+	if reqErr != nil {
+		respondWithRequestError(w, reqErr)
+		return
+	}
+	var req templateRequest
+	if reqErr := decodeJSONBody(w, r, &req); reqErr != nil {
+		respondWithRequestError(w, reqErr)
+		return
+	}
+	req, reqErr = cfg.validateTemplateRequest(req)
+	if reqErr != nil {
+		respondWithRequestError(w, reqErr)
+		return
+	}
+
+	template, err := cfg.db.UpdateChirpTemplate(r.Context(), database.UpdateChirpTemplateParams{
+		ID:        templateID,
+		UserID:    userIDFromContext(r.Context()),
+		Name:      req.Name,
+		Body:      req.Body,
+		UpdatedAt: cfg.clock.Now().UTC(),
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		respondWithError(w, http.StatusNotFound, "Template not found", nil)
+		return
+	}
+	if isUniqueViolation(err, "chirp_templates_user_id_name_key") {
+		respondWithError(w, http.StatusConflict, "A template with this name already exists", nil)
+		return
+	}
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't update template", err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, templateJSON(template))
+}
+
+// Handler für /api/users/me/templates/{templateID} (DELETE)
+func (cfg *apiConfig) handlerTemplateDelete(w http.ResponseWriter, r *http.Request) {
+	templateID, reqErr := templateIDFromPath(r)
+	if reqErr != nil {
+		respondWithRequestError(w, reqErr)
+		return
+	}
+
+	rows, err := cfg.db.DeleteChirpTemplate(r.Context(), database.DeleteChirpTemplateParams{
+		ID:     templateID,
+		UserID: userIDFromContext(r.Context()),
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't delete template", err)
+		return
+	}
+	if rows == 0 {
+		respondWithError(w, http.StatusNotFound, "Template not found", nil)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func templateIDFromPath(r *http.Request) (uuid.UUID, *requestError) {
+	templateID, err := uuid.Parse(r.PathValue("templateID"))
+	if err != nil {
+		return uuid.Nil, &requestError{status: http.StatusBadRequest, msg: "Invalid template ID", err: err}
+	}
+	return templateID, nil
+}
+
+// Lädt eine Vorlage des angemeldeten Users; fremde Vorlagen gelten als nicht vorhanden.
+func (cfg *apiConfig) templateForUser(r *http.Request, templateID uuid.UUID) (database.ChirpTemplate, *requestError) {
+	template, err := cfg.db.GetChirpTemplate(r.Context(), database.GetChirpTemplateParams{
+		ID:     templateID,
+		UserID: userIDFromContext(r.Context()),
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return database.ChirpTemplate{}, &requestError{status: http.StatusNotFound, msg: "Template not found"}
+	}
+	if err != nil {
+		return database.ChirpTemplate{}, &requestError{status: http.StatusInternalServerError, msg: "Couldn't retrieve template", err: err}
+	}
+	return template, nil
+}
+
+// Wandelt eine Vorlage aus der DB in ihre JSON-Darstellung um.
+func templateJSON(t database.ChirpTemplate) chirpy.ChirpTemplate {
+	return chirpy.ChirpTemplate{
+		ID:        t.ID,
+		Name:      t.Name,
+		Body:      t.Body,
+		CreatedAt: t.CreatedAt,
+		UpdatedAt: t.UpdatedAt,
+	}
+}
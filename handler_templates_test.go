@@ -0,0 +1,140 @@
+package main
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/nuke87/go_http_server/pkg/chirpy"
+)
+
+// Auch parallele Requests legen nicht mehr als maxTemplatesPerUser Vorlagen an.
+func TestTemplateCreateQuota(t *testing.T) {
+	ts := newTestServer(t)
+	_, token := ts.createUser(t, "alice@example.com")
+	_, bobToken := ts.createUser(t, "bob@example.com")
+
+	const requests = maxTemplatesPerUser + 10
+	codes := make([]int, requests)
+	var wg sync.WaitGroup
+	for i := range codes {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			body := `{"name":"t` + strconv.Itoa(i) + `","body":"hello"}`
+			codes[i] = ts.do(t, "POST", "/api/users/me/templates", token, body).Code
+		}(i)
+	}
+	wg.Wait()
+
+	created, rejected := 0, 0
+	for _, code := range codes {
+		switch code {
+		case http.StatusCreated:
+			created++
+		case http.StatusConflict:
+			rejected++
+		default:
+			t.Errorf("unexpected status %d", code)
+		}
+	}
+	if created != maxTemplatesPerUser || rejected != requests-maxTemplatesPerUser {
+		t.Errorf("%d created, %d rejected; want %d and %d", created, rejected, maxTemplatesPerUser, requests-maxTemplatesPerUser)
+	}
+
+	rec := ts.do(t, "GET", "/api/users/me/templates", token, "")
+	expectStatus(t, rec, http.StatusOK)
+	if got := decodeResponse[[]chirpy.ChirpTemplate](t, rec); len(got) != maxTemplatesPerUser {
+		t.Errorf("listed %d templates, want %d", len(got), maxTemplatesPerUser)
+	}
+
+	// Das Limit gilt pro User
+	expectStatus(t, ts.do(t, "POST", "/api/users/me/templates", bobToken, `{"name":"t0","body":"hello"}`), http.StatusCreated)
+}
+
+func TestRenderTemplate(t *testing.T) {
+	tests := []struct {
+		name         string
+		body         string
+		variables    map[string]string
+		want         string
+		wantWarnings []string
+	}{
+		{"no placeholders", "hello", nil, "hello", nil},
+		{"substituted", "hi {{name}}, see {{ place }}", map[string]string{"name": "Bob", "place": "Berlin"}, "hi Bob, see Berlin", nil},
+		{"repeated", "{{x}}{{x}}", map[string]string{"x": "ab"}, "abab", nil},
+		{"unknown is empty and warned once", "a{{missing}}b{{missing}}", nil, "ab",
+			[]string{`unknown template variable "missing" rendered as empty string`}},
+		{"one warning per unknown name", "{{a}} {{b}}", map[string]string{}, " ",
+			[]string{`unknown template variable "a" rendered as empty string`, `unknown template variable "b" rendered as empty string`}},
+		{"values are not rendered again", "{{x}}", map[string]string{"x": "{{y}}", "y": "no"}, "{{y}}", nil},
+		{"not a placeholder", "{{not valid}} {x} {{}}", nil, "{{not valid}} {x} {{}}", nil},
+		{"unused variables are ignored", "hi", map[string]string{"name": "Bob"}, "hi", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, warnings := renderTemplate(tt.body, tt.variables)
+			if got != tt.want || !slices.Equal(warnings, tt.wantWarnings) {
+				t.Errorf("renderTemplate(%q) = %q, %q; want %q, %q", tt.body, got, warnings, tt.want, tt.wantWarnings)
+			}
+		})
+	}
+}
+
+func TestCreateChirpFromTemplate(t *testing.T) {
+	ts := newTestServer(t)
+	_, token := ts.createUser(t, "alice@example.com")
+	_, bobToken := ts.createUser(t, "bob@example.com")
+	createTemplate := func(token, name, body string) string {
+		t.Helper()
+		rec := ts.do(t, "POST", "/api/users/me/templates", token, `{"name":"`+name+`","body":"`+body+`"}`)
+		expectStatus(t, rec, http.StatusCreated)
+		return decodeResponse[chirpy.ChirpTemplate](t, rec).ID.String()
+	}
+	greet := createTemplate(token, "greet", "hi {{name}}, what a {{word}}")
+	long := createTemplate(token, "long", strings.Repeat("a", 130)+"{{tail}}")
+	empty := createTemplate(token, "empty", "{{nothing}}")
+	bobs := createTemplate(bobToken, "bobs", "hello")
+
+	tests := []struct {
+		name         string
+		body         string
+		status       int
+		wantBody     string
+		wantWarnings []string
+		wantCode     string
+	}{
+		{"substituted and cleaned", `{"template_id":"` + greet + `","variables":{"name":"Bob","word":"kerfuffle"}}`,
+			http.StatusCreated, "hi Bob, what a ****", nil, ""},
+		{"unknown variable warns", `{"template_id":"` + greet + `","variables":{"name":"Bob"}}`,
+			http.StatusCreated, "hi Bob, what a ", []string{`unknown template variable "word" rendered as empty string`}, ""},
+		{"at the limit after substitution", `{"template_id":"` + long + `","variables":{"tail":"` + strings.Repeat("b", 10) + `"}}`,
+			http.StatusCreated, strings.Repeat("a", 130) + strings.Repeat("b", 10), nil, ""},
+		{"too long after substitution", `{"template_id":"` + long + `","variables":{"tail":"` + strings.Repeat("b", 11) + `"}}`,
+			http.StatusBadRequest, "", nil, errCodeTooLong},
+		{"renders to nothing", `{"template_id":"` + empty + `"}`, http.StatusBadRequest, "", nil, errCodeMissingField},
+		{"missing template", `{"template_id":"` + uuid.NewString() + `"}`, http.StatusNotFound, "", nil, ""},
+		{"someone else's template", `{"template_id":"` + bobs + `"}`, http.StatusNotFound, "", nil, ""},
+		{"body and template", `{"body":"x","template_id":"` + greet + `"}`, http.StatusBadRequest, "", nil, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := ts.do(t, "POST", "/api/chirps", token, tt.body)
+			expectStatus(t, rec, tt.status)
+			if tt.status != http.StatusCreated {
+				if got := decodeResponse[chirpy.ErrorResponse](t, rec); got.Code != tt.wantCode {
+					t.Errorf("code = %q, want %q", got.Code, tt.wantCode)
+				}
+				return
+			}
+			got := decodeResponse[chirpy.Chirp](t, rec)
+			if got.Body != tt.wantBody || !slices.Equal(got.Warnings, tt.wantWarnings) {
+				t.Errorf("chirp = %q, warnings %q; want %q, %q", got.Body, got.Warnings, tt.wantBody, tt.wantWarnings)
+			}
+		})
+	}
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Fixture-Endpunkte für End-to-End-Tests von Clients. Sie werden nur bei PLATFORM=dev
+// registriert (siehe routes) und existieren sonst nicht (404).
+
+// Handler für /admin/testing/users/{userID} (DELETE)
+// Löscht einen User endgültig samt seiner Chirps.
+func (cfg *apiConfig) handlerTestingDeleteUser(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuid.Parse(r.PathValue("userID"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid user ID", err)
+		return
+	}
+
+	deletedChirps, err := cfg.db.DeleteChirpsByUser(r.Context(), userID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't delete chirps", err)
+		return
+	}
+	deletedUsers, err := cfg.db.DeleteUser(r.Context(), userID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't delete user", err)
+		return
+	}
+	if deletedUsers == 0 {
+		respondWithError(w, http.StatusNotFound, "User not found", nil)
+		return
+	}
+
+	type response struct {
+		DeletedUsers  int64 `json:"deleted_users"`
+		DeletedChirps int64 `json:"deleted_chirps"`
+	}
+	respondWithJSON(w, http.StatusOK, response{
+		DeletedUsers:  deletedUsers,
+		DeletedChirps: deletedChirps,
+	})
+}
+
+// Handler für /admin/testing/chirps (DELETE)
+// Löscht alle Chirps, User bleiben erhalten.
+func (cfg *apiConfig) handlerTestingDeleteChirps(w http.ResponseWriter, r *http.Request) {
+	deleted, err := cfg.db.DeleteAllChirps(r.Context())
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't delete chirps", err)
+		return
+	}
+
+	type response struct {
+		DeletedChirps int64 `json:"deleted_chirps"`
+	}
+	respondWithJSON(w, http.StatusOK, response{DeletedChirps: deleted})
+}
+
+// Größte Verschiebung pro Request (etwa zehn Jahre); darüber liefe time.Duration über.
+const maxTimeTravelSeconds = 10 * 365 * 24 * 60 * 60
+
+// Handler für /admin/testing/time-travel (POST)
+// Verschiebt die Serveruhr um offset_seconds (auch negativ, höchstens maxTimeTravelSeconds).
+func (cfg *apiConfig) handlerTestingTimeTravel(w http.ResponseWriter, r *http.Request) {
+	type parameters struct {
+		OffsetSeconds jsonNumber `json:"offset_seconds"`
+	}
+	var params parameters
+	if reqErr := decodeJSONBody(w, r, &params); reqErr != nil {
+		respondWithRequestError(w, reqErr)
+		return
+	}
+	if !params.OffsetSeconds.isSet() {
+		respondWithErrorCode(w, http.StatusBadRequest, errCodeMissingField, "offset_seconds is required", nil)
+		return
+	}
+	offsetSeconds, reqErr := params.OffsetSeconds.Int("offset_seconds", math.MinInt64, math.MaxInt64)
+	if reqErr != nil {
+		respondWithRequestError(w, reqErr)
+		return
+	}
+	if offsetSeconds < -maxTimeTravelSeconds || offsetSeconds > maxTimeTravelSeconds {
+		respondWithRequestError(w, &requestError{status: http.StatusBadRequest, code: errCodeInvalidField, field: "offset_seconds",
+			msg: fmt.Sprintf("offset_seconds must be between %d and %d", -maxTimeTravelSeconds, maxTimeTravelSeconds)})
+		return
+	}
+
+	total := cfg.clock.Shift(time.Duration(offsetSeconds) * time.Second)
+
+	type response struct {
+		ShiftedBySeconds   int64     `json:"shifted_by_seconds"`
+		TotalOffsetSeconds int64     `json:"total_offset_seconds"`
+		Now                time.Time `json:"now"`
+	}
+	respondWithJSON(w, http.StatusOK, response{
+		ShiftedBySeconds:   offsetSeconds,
+		TotalOffsetSeconds: int64(total / time.Second),
+		Now:                cfg.clock.Now().UTC(),
+	})
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nuke87/go_http_server/pkg/chirpy"
+)
+
+// Außerhalb von dev gibt es die Testing-Routen nicht: 404 auch mit Admin-Zugang, nicht 401 oder 403.
+func TestTestingRoutesNotInProduction(t *testing.T) {
+	ts := newTestServer(t, func(cfg *apiConfig) {
+		cfg.platform = "production"
+		cfg.adminToken = "admin-secret"
+	})
+	for _, rt := range ts.cfg.routeTable {
+		if strings.HasPrefix(rt.Pattern, "/admin/testing/") {
+			t.Errorf("production registers %s %s", rt.Method, rt.Pattern)
+		}
+	}
+
+	endpoints := []struct{ method, path, body string }{
+		{"DELETE", "/admin/testing/users/" + uuid.NewString(), ""},
+		{"DELETE", "/admin/testing/chirps", ""},
+		{"POST", "/admin/testing/time-travel", `{"offset_seconds":60}`},
+	}
+	for _, e := range endpoints {
+		for _, token := range []string{"", "admin-secret"} {
+			if rec := ts.do(t, e.method, e.path, token, e.body); rec.Code != http.StatusNotFound {
+				t.Errorf("%s %s (token %q): status %d, want 404", e.method, e.path, token, rec.Code)
+			}
+		}
+	}
+	if !ts.cfg.clock.Now().Equal(ts.now) {
+		t.Errorf("clock moved to %s", ts.cfg.clock.Now())
+	}
+}
+
+func TestTimeTravel(t *testing.T) {
+	ts := newTestServer(t)
+	travel := func(seconds string) chirpy.ErrorResponse {
+		t.Helper()
+		rec := ts.do(t, "POST", "/admin/testing/time-travel", "", `{"offset_seconds":`+seconds+`}`)
+		if rec.Code == http.StatusOK {
+			return chirpy.ErrorResponse{}
+		}
+		expectStatus(t, rec, http.StatusBadRequest)
+		return decodeResponse[chirpy.ErrorResponse](t, rec)
+	}
+
+	travel("3600")
+	travel("-60")
+	if got, want := ts.cfg.clock.Now(), ts.now.Add(59*time.Minute); !got.Equal(want) {
+		t.Errorf("clock = %s, want %s", got, want)
+	}
+	travel(strconv.Itoa(maxTimeTravelSeconds))
+	travel(strconv.Itoa(-maxTimeTravelSeconds))
+
+	// Zu große Verschiebungen würden time.Duration überlaufen lassen
+	for _, seconds := range []string{strconv.Itoa(maxTimeTravelSeconds + 1), "-" + strconv.Itoa(maxTimeTravelSeconds+1), "9223372036854775807"} {
+		got := travel(seconds)
+		if got.Code != errCodeInvalidField || got.Field != "offset_seconds" {
+			t.Errorf("offset_seconds %s: %+v, want code %s", seconds, got, errCodeInvalidField)
+		}
+	}
+	if got, want := ts.cfg.clock.Now(), ts.now.Add(59*time.Minute); !got.Equal(want) {
+		t.Errorf("clock = %s after rejected shifts, want %s", got, want)
+	}
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/nuke87/go_http_server/pkg/chirpy"
+)
+
+// Handler für /api/users/{userID} (GET)
+// Gibt den User ohne Passwort-Hash zurück; 400 bei ungültiger ID, 404 wenn es ihn nicht gibt.
+func (cfg *apiConfig) handlerUserGet(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuid.Parse(r.PathValue("userID"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid user ID", err)
+		return
+	}
+
+	user, err := cfg.db.GetUserByID(r.Context(), userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		respondWithError(w, http.StatusNotFound, "User not found", nil)
+		return
+	}
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't retrieve user", err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, userJSON(user))
+}
+
+// Handler für /admin/users (GET)
+// Gibt alle User zurück, älteste zuerst. Zugang regelt middlewareAdmin (PLATFORM=dev oder Admin-Rechte).
+func (cfg *apiConfig) handlerAdminUsers(w http.ResponseWriter, r *http.Request) {
+	dbUsers, err := cfg.db.GetUsers(r.Context())
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't retrieve users", err)
+		return
+	}
+
+	users := make([]chirpy.User, 0, len(dbUsers))
+	for _, u := range dbUsers {
+		users = append(users, userJSON(u))
+	}
+	respondWithJSON(w, http.StatusOK, users)
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nuke87/go_http_server/pkg/chirpy"
+)
+
+func TestUserGet(t *testing.T) {
+	ts := newTestServer(t)
+	user, _ := ts.createUser(t, "alice@example.com")
+
+	rec := ts.do(t, "GET", "/api/users/"+user.ID.String(), "", "")
+	expectStatus(t, rec, http.StatusOK)
+	if got := decodeResponse[chirpy.User](t, rec); got.ID != user.ID || got.Email != user.Email {
+		t.Errorf("user = %+v, want %+v", got, user)
+	}
+	if body := rec.Body.String(); strings.Contains(body, "password") || strings.Contains(body, "$2a$") {
+		t.Errorf("response leaks the password hash: %s", body)
+	}
+
+	expectStatus(t, ts.do(t, "GET", "/api/users/"+uuid.NewString(), "", ""), http.StatusNotFound)
+	expectStatus(t, ts.do(t, "GET", "/api/users/not-a-uuid", "", ""), http.StatusBadRequest)
+}
+
+func TestAdminUsers(t *testing.T) {
+	t.Run("dev", func(t *testing.T) {
+		ts := newTestServer(t)
+		alice, _ := ts.createUser(t, "alice@example.com")
+		ts.advance(time.Second)
+		bob, _ := ts.createUser(t, "bob@example.com")
+
+		rec := ts.do(t, "GET", "/admin/users", "", "")
+		expectStatus(t, rec, http.StatusOK)
+		got := decodeResponse[[]chirpy.User](t, rec)
+		if len(got) != 2 || got[0].ID != alice.ID || got[1].ID != bob.ID {
+			t.Errorf("users = %+v, want alice then bob", got)
+		}
+	})
+
+	t.Run("forbidden outside dev", func(t *testing.T) {
+		ts := newTestServer(t, func(cfg *apiConfig) { cfg.platform = "production" })
+		_, token := ts.createUser(t, "alice@example.com")
+		expectStatus(t, ts.do(t, "GET", "/admin/users", "", ""), http.StatusUnauthorized)
+		expectStatus(t, ts.do(t, "GET", "/admin/users", token, ""), http.StatusForbidden)
+	})
+
+	t.Run("admin outside dev", func(t *testing.T) {
+		ts := newTestServer(t, func(cfg *apiConfig) {
+			cfg.platform = "production"
+			cfg.adminToken = "admin-secret"
+		})
+		ts.createUser(t, "alice@example.com")
+		rec := ts.do(t, "GET", "/admin/users", "admin-secret", "")
+		expectStatus(t, rec, http.StatusOK)
+		if got := decodeResponse[[]chirpy.User](t, rec); len(got) != 1 {
+			t.Errorf("got %d users, want 1", len(got))
+		}
+	})
+}
@@ -0,0 +1,159 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nuke87/go_http_server/internal/auth"
+	"github.com/nuke87/go_http_server/pkg/chirpy"
+)
+
+const testJWTSecret = "test-secret-0123456789abcdefghijklmnopqrstuvwxyzABCDEF"
+
+func TestMain(m *testing.M) {
+	// Das Request-Log von middlewareLogging würde die Testausgabe überfluten
+	slog.SetDefault(slog.New(slog.NewTextHandler(io.Discard, nil)))
+	os.Exit(m.Run())
+}
+
+// testServer ist ein Server mit memoryStore und fester Uhr, ohne Netzwerk.
+type testServer struct {
+	cfg     *apiConfig
+	store   *memoryStore
+	now     time.Time // Aktuelle Zeit der Server-Uhr, per advance verschiebbar
+	handler http.Handler
+}
+
+// Baut einen testServer. configure läuft vor registerRoutes und kann z.B. platform setzen.
+func newTestServer(t *testing.T, configure ...func(*apiConfig)) *testServer {
+	t.Helper()
+	store := newMemoryStore()
+	ts := &testServer{
+		store: store,
+		now:   time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC),
+	}
+	cfg := &apiConfig{
+		db:              store,
+		dbPinger:        store,
+		platform:        "dev",
+		baseURL:         "http://chirpy.test",
+		stripDiacritics: true,
+		translator:      noopTranslator{},
+		translateLangs:  []string{"de", "en"},
+		jwtSecret:       testJWTSecret,
+		jwtExpiresIn:    time.Hour,
+		editWindow:      defaultChirpEditWindow,
+		bidiPolicy:      bidiStrip,
+		polkaKey:        "test-polka-key",
+		bannedWords:     defaultBannedWords,
+		startedAt:       ts.now,
+		requestMetrics:  newRequestMetrics(),
+		metricsExclude:  map[string]bool{},
+		rateLimiters:    map[string]*rateLimiter{rateLimitLogin: nil, rateLimitChirp: nil},
+		adminUserIDs:    map[uuid.UUID]bool{},
+	}
+	cfg.clock.now = func() time.Time { return ts.now }
+	for _, c := range configure {
+		c(cfg)
+	}
+	mux := http.NewServeMux()
+	if err := cfg.registerRoutes(mux, cfg.routes(t.TempDir())); err != nil {
+		t.Fatalf("registerRoutes: %v", err)
+	}
+	ts.cfg = cfg
+	ts.handler = cfg.serverHandler(mux)
+	return ts
+}
+
+// Stellt die Server-Uhr um d vor.
+func (ts *testServer) advance(d time.Duration) {
+	ts.now = ts.now.Add(d)
+}
+
+// Schickt einen Request; body ist JSON oder leer, token wird als Bearer gesetzt, wenn nicht leer.
+func (ts *testServer) do(t *testing.T, method, path, token, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	var reader io.Reader
+	if body != "" {
+		reader = strings.NewReader(body)
+	}
+	req := httptest.NewRequest(method, path, reader)
+	if body != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	return ts.serve(req)
+}
+
+func (ts *testServer) serve(req *http.Request) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	ts.handler.ServeHTTP(rec, req)
+	return rec
+}
+
+// Legt einen User über POST /api/users an und liefert ihn samt Access-Token.
+func (ts *testServer) createUser(t *testing.T, email string) (chirpy.User, string) {
+	t.Helper()
+	rec := ts.do(t, "POST", "/api/users", "", `{"email":"`+email+`","password":"hunter22"}`)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("create user %s: status %d, body %s", email, rec.Code, rec.Body)
+	}
+	user := decodeResponse[chirpy.User](t, rec)
+	return user, ts.token(t, user.ID)
+}
+
+// Access-Token für userID, gültig zur aktuellen Server-Zeit.
+func (ts *testServer) token(t *testing.T, userID uuid.UUID) string {
+	t.Helper()
+	token, err := auth.MakeJWT(userID, testJWTSecret, ts.now, time.Hour)
+	if err != nil {
+		t.Fatalf("MakeJWT: %v", err)
+	}
+	return token
+}
+
+// Legt einen Chirp über POST /api/chirps an.
+func (ts *testServer) createChirp(t *testing.T, token, body string) chirpy.Chirp {
+	t.Helper()
+	payload, _ := json.Marshal(map[string]string{"body": body})
+	rec := ts.do(t, "POST", "/api/chirps", token, string(payload))
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("create chirp: status %d, body %s", rec.Code, rec.Body)
+	}
+	return decodeResponse[chirpy.Chirp](t, rec)
+}
+
+func decodeResponse[T any](t *testing.T, rec *httptest.ResponseRecorder) T {
+	t.Helper()
+	var v T
+	if err := json.Unmarshal(rec.Body.Bytes(), &v); err != nil {
+		t.Fatalf("decode response %q: %v", rec.Body, err)
+	}
+	return v
+}
+
+// Prüft Status und, bei Fehlern, dass die Antwort ein JSON-Fehlerobjekt ist.
+func expectStatus(t *testing.T, rec *httptest.ResponseRecorder, want int) {
+	t.Helper()
+	if rec.Code != want {
+		t.Fatalf("status = %d, want %d, body %s", rec.Code, want, rec.Body)
+	}
+	if want >= 400 {
+		if ct := rec.Header().Get("Content-Type"); ct != "application/json" {
+			t.Errorf("Content-Type = %q, want application/json", ct)
+		}
+		if e := decodeResponse[chirpy.ErrorResponse](t, rec); e.Error == "" {
+			t.Errorf("error response without message: %s", rec.Body)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+
+	"github.com/nuke87/go_http_server/internal/database"
+)
+
+// Liefert die Indizes aus database.RequiredIndexes, die im aktuellen Schema fehlen.
+func missingIndexes(dbConn *sql.DB) ([]database.Index, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	names := make([]string, 0, len(database.RequiredIndexes))
+	for _, idx := range database.RequiredIndexes {
+		names = append(names, idx.Name)
+	}
+	rows, err := dbConn.QueryContext(ctx,
+		"SELECT indexname FROM pg_indexes WHERE schemaname = current_schema() AND indexname = ANY($1)",
+		pq.Array(names))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	present := map[string]bool{}
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		present[name] = true
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	var missing []database.Index
+	for _, idx := range database.RequiredIndexes {
+		if !present[idx.Name] {
+			missing = append(missing, idx)
+		}
+	}
+	return missing, nil
+}
+
+// Legt fehlende Indizes mit CREATE INDEX CONCURRENTLY an (AUTO_CREATE_INDEXES=true).
+// Läuft im Hintergrund, damit der Start nicht auf große Tabellen wartet.
+func createIndexes(dbConn *sql.DB, indexes []database.Index) {
+	for _, idx := range indexes {
+		stmt := strings.Replace(idx.Create, "CREATE INDEX", "CREATE INDEX CONCURRENTLY", 1)
+		start := time.Now()
+		if _, err := dbConn.Exec(stmt); err != nil {
+			slog.Error("creating index failed", "index", idx.Name, "error", err)
+			continue
+		}
+		slog.Info("created index", "index", idx.Name, "duration", time.Since(start).String())
+	}
+}
@@ -0,0 +1,20 @@
+package auth
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+)
+
+// GetAPIKey liest den Key aus "Authorization: ApiKey <key>".
+func GetAPIKey(headers http.Header) (string, error) {
+	authHeader := headers.Get("Authorization")
+	if authHeader == "" {
+		return "", ErrNoAuthHeader
+	}
+	scheme, key, ok := strings.Cut(authHeader, " ")
+	if !ok || !strings.EqualFold(scheme, "ApiKey") || strings.TrimSpace(key) == "" {
+		return "", errors.New("malformed authorization header")
+	}
+	return strings.TrimSpace(key), nil
+}
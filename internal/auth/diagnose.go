@@ -0,0 +1,120 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+// Empfohlene Mindestlänge für das HS256-Secret
+const minSecretLength = 32
+
+// Chirpy signiert mit genau einem Secret und vergibt keine Key-IDs.
+var errKidNotFound = errors.New("kid not found")
+
+// CheckSecret liefert Warnungen zu einem JWT-Secret: zu kurz oder mit Whitespace,
+// z.B. einem versehentlich mitkopierten Zeilenumbruch.
+func CheckSecret(secret string) []string {
+	var warnings []string
+	if len(secret) < minSecretLength {
+		warnings = append(warnings, fmt.Sprintf("JWT_SECRET is %d bytes, use at least %d", len(secret), minSecretLength))
+	}
+	if strings.IndexFunc(secret, unicode.IsSpace) >= 0 {
+		warnings = append(warnings, "JWT_SECRET contains whitespace, check for a trailing newline")
+	}
+	return warnings
+}
+
+// SelfCheck stellt ein Token aus und prüft es sofort wieder.
+func SelfCheck(secret string, now time.Time) error {
+	userID := uuid.New()
+	token, err := MakeJWT(userID, secret, now, time.Minute)
+	if err != nil {
+		return fmt.Errorf("minting a token failed: %w", err)
+	}
+	got, err := ValidateJWT(token, secret, now)
+	if err != nil {
+		return fmt.Errorf("validating a freshly minted token failed: %w", err)
+	}
+	if got != userID {
+		return fmt.Errorf("validated token has subject %s, want %s", got, userID)
+	}
+	return nil
+}
+
+// Diagnosis beschreibt, ob und an welchem Prüfschritt ein Token scheitert.
+type Diagnosis struct {
+	Valid     bool       `json:"valid"`
+	Step      string     `json:"step,omitempty"`  // format, kid, algorithm, signature, expiry, issuer, subject
+	Error     string     `json:"error,omitempty"` // Enthält nie das Token selbst
+	Subject   string     `json:"subject,omitempty"`
+	Issuer    string     `json:"issuer,omitempty"`
+	IssuedAt  *time.Time `json:"issued_at,omitempty"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+// DiagnoseJWT prüft ein Token wie ValidateJWT, meldet aber den fehlgeschlagenen Schritt
+// und die lesbaren Claims, auch wenn die Prüfung scheitert.
+func DiagnoseJWT(tokenString, tokenSecret string, now time.Time) Diagnosis {
+	claims := jwt.RegisteredClaims{}
+	token, err := jwt.ParseWithClaims(
+		tokenString,
+		&claims,
+		func(token *jwt.Token) (interface{}, error) {
+			if _, ok := token.Header["kid"]; ok {
+				return nil, errKidNotFound
+			}
+			return []byte(tokenSecret), nil
+		},
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}),
+		jwt.WithIssuer(tokenIssuer),
+		jwt.WithExpirationRequired(),
+		jwt.WithTimeFunc(func() time.Time { return now }),
+	)
+
+	d := Diagnosis{Subject: claims.Subject, Issuer: claims.Issuer}
+	if claims.IssuedAt != nil {
+		d.IssuedAt = &claims.IssuedAt.Time
+	}
+	if claims.ExpiresAt != nil {
+		d.ExpiresAt = &claims.ExpiresAt.Time
+	}
+	if err != nil {
+		d.Step = diagnoseStep(err, token)
+		d.Error = err.Error()
+		return d
+	}
+	if _, err := uuid.Parse(claims.Subject); err != nil {
+		d.Step = "subject"
+		d.Error = "subject is not a user ID"
+		return d
+	}
+	d.Valid = true
+	return d
+}
+
+// Ordnet einen Fehler von jwt.ParseWithClaims dem Prüfschritt zu.
+func diagnoseStep(err error, token *jwt.Token) string {
+	switch {
+	case errors.Is(err, jwt.ErrTokenMalformed):
+		return "format"
+	case errors.Is(err, errKidNotFound):
+		return "kid"
+	case errors.Is(err, jwt.ErrTokenSignatureInvalid):
+		if token != nil && token.Method != nil && token.Method.Alg() != jwt.SigningMethodHS256.Alg() {
+			return "algorithm"
+		}
+		return "signature"
+	case errors.Is(err, jwt.ErrTokenExpired), errors.Is(err, jwt.ErrTokenNotValidYet),
+		errors.Is(err, jwt.ErrTokenUsedBeforeIssued), errors.Is(err, jwt.ErrTokenRequiredClaimMissing):
+		return "expiry"
+	case errors.Is(err, jwt.ErrTokenInvalidIssuer):
+		return "issuer"
+	}
+	return "signature"
+}
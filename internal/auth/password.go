@@ -0,0 +1,30 @@
+// Package auth enthält Passwort-Hashing und weitere Authentifizierungs-Helfer.
+package auth
+
+import "golang.org/x/crypto/bcrypt"
+
+// MaxPasswordBytes ist die Längengrenze von bcrypt; längere Passwörter lehnt HashPassword ab.
+const MaxPasswordBytes = 72
+
+// HashPassword erzeugt einen bcrypt-Hash des Passworts.
+func HashPassword(password string) (string, error) {
+	dat, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", err
+	}
+	return string(dat), nil
+}
+
+// CheckPasswordHash liefert nil, wenn das Passwort zum Hash passt.
+func CheckPasswordHash(password, hash string) error {
+	return bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
+}
+
+// Hash eines zufälligen Passworts mit DefaultCost, keinem echten Passwort zugeordnet.
+const dummyHash = "$2a$10$7EqJtq98hPqEX7fNZaFWoOhi5BWX4Z3sB6j1Z6C6IsU7YdG8xb4bC"
+
+// CheckDummyPassword kostet so viel wie CheckPasswordHash und schlägt immer fehl. Für
+// unbekannte Accounts, damit die Antwortzeit nicht verrät, ob es die E-Mail gibt.
+func CheckDummyPassword(password string) {
+	_ = bcrypt.CompareHashAndPassword([]byte(dummyHash), []byte(password))
+}
@@ -0,0 +1,36 @@
+package auth
+
+import (
+	"strings"
+	"testing"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestHashPasswordLimit(t *testing.T) {
+	hash, err := HashPassword(strings.Repeat("a", MaxPasswordBytes))
+	if err != nil {
+		t.Fatalf("HashPassword at limit: %v", err)
+	}
+	if err := CheckPasswordHash(strings.Repeat("a", MaxPasswordBytes), hash); err != nil {
+		t.Errorf("CheckPasswordHash: %v", err)
+	}
+	if _, err := HashPassword(strings.Repeat("a", MaxPasswordBytes+1)); err == nil {
+		t.Error("HashPassword accepted a password over the bcrypt limit")
+	}
+}
+
+// Der Dummy-Hash muss ein gültiger Hash mit DefaultCost sein, sonst bricht bcrypt
+// sofort ab und die Antwortzeit verrät unbekannte Accounts.
+func TestDummyHashCost(t *testing.T) {
+	cost, err := bcrypt.Cost([]byte(dummyHash))
+	if err != nil {
+		t.Fatalf("dummyHash is not a bcrypt hash: %v", err)
+	}
+	if cost != bcrypt.DefaultCost {
+		t.Errorf("dummyHash cost = %d, want %d", cost, bcrypt.DefaultCost)
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(dummyHash), []byte("hunter22")); err != bcrypt.ErrMismatchedHashAndPassword {
+		t.Errorf("compare with dummyHash: err = %v, want a plain mismatch", err)
+	}
+}
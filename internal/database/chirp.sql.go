@@ -7,23 +7,26 @@ package database
 
 import (
 	"context"
+	"encoding/json"
 	"time"
 
 	"github.com/google/uuid"
 )
 
 const createChirp = `-- name: CreateChirp :one
-INSERT INTO chirps (id, created_at, updated_at, body, user_id)
-VALUES ($1, $2, $3, $4, $5)
-RETURNING id, created_at, updated_at, body, user_id
+INSERT INTO chirps (id, created_at, updated_at, body, user_id, short_id, masked_ranges)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+RETURNING id, created_at, updated_at, body, user_id, short_id, masked_ranges
 `
 
 type CreateChirpParams struct {
-	ID        uuid.UUID
-	CreatedAt time.Time
-	UpdatedAt time.Time
-	Body      string
-	UserID    uuid.UUID
+	ID           uuid.UUID
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+	Body         string
+	UserID       uuid.UUID
+	ShortID      string
+	MaskedRanges json.RawMessage
 }
 
 func (q *Queries) CreateChirp(ctx context.Context, arg CreateChirpParams) (Chirp, error) {
@@ -33,6 +36,8 @@ func (q *Queries) CreateChirp(ctx context.Context, arg CreateChirpParams) (Chirp
 		arg.UpdatedAt,
 		arg.Body,
 		arg.UserID,
+		arg.ShortID,
+		arg.MaskedRanges,
 	)
 	var i Chirp
 	err := row.Scan(
@@ -41,6 +46,464 @@ func (q *Queries) CreateChirp(ctx context.Context, arg CreateChirpParams) (Chirp
 		&i.UpdatedAt,
 		&i.Body,
 		&i.UserID,
+		&i.ShortID,
+		&i.MaskedRanges,
+	)
+	return i, err
+}
+
+const getChirpByShortID = `-- name: GetChirpByShortID :one
+SELECT id, created_at, updated_at, body, user_id, short_id, masked_ranges FROM chirps
+WHERE short_id = $1
+`
+
+func (q *Queries) GetChirpByShortID(ctx context.Context, shortID string) (Chirp, error) {
+	row := q.db.QueryRowContext(ctx, getChirpByShortID, shortID)
+	var i Chirp
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Body,
+		&i.UserID,
+		&i.ShortID,
+		&i.MaskedRanges,
+	)
+	return i, err
+}
+
+const getChirpsAsc = `-- name: GetChirpsAsc :many
+SELECT id, created_at, updated_at, body, user_id, short_id, masked_ranges FROM chirps
+ORDER BY created_at, id
+`
+
+func (q *Queries) GetChirpsAsc(ctx context.Context) ([]Chirp, error) {
+	rows, err := q.db.QueryContext(ctx, getChirpsAsc)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Chirp
+	for rows.Next() {
+		var i Chirp
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Body,
+			&i.UserID,
+			&i.ShortID,
+			&i.MaskedRanges,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getChirpsDesc = `-- name: GetChirpsDesc :many
+SELECT id, created_at, updated_at, body, user_id, short_id, masked_ranges FROM chirps
+ORDER BY created_at DESC, id DESC
+`
+
+func (q *Queries) GetChirpsDesc(ctx context.Context) ([]Chirp, error) {
+	rows, err := q.db.QueryContext(ctx, getChirpsDesc)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Chirp
+	for rows.Next() {
+		var i Chirp
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Body,
+			&i.UserID,
+			&i.ShortID,
+			&i.MaskedRanges,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getChirpByID = `-- name: GetChirpByID :one
+SELECT id, created_at, updated_at, body, user_id, short_id, masked_ranges FROM chirps
+WHERE id = $1
+`
+
+func (q *Queries) GetChirpByID(ctx context.Context, id uuid.UUID) (Chirp, error) {
+	row := q.db.QueryRowContext(ctx, getChirpByID, id)
+	var i Chirp
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Body,
+		&i.UserID,
+		&i.ShortID,
+		&i.MaskedRanges,
+	)
+	return i, err
+}
+
+const deleteAllChirps = `-- name: DeleteAllChirps :execrows
+DELETE FROM chirps
+`
+
+func (q *Queries) DeleteAllChirps(ctx context.Context) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteAllChirps)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const deleteChirpsByUser = `-- name: DeleteChirpsByUser :execrows
+DELETE FROM chirps
+WHERE user_id = $1
+`
+
+func (q *Queries) DeleteChirpsByUser(ctx context.Context, userID uuid.UUID) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteChirpsByUser, userID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const deleteChirp = `-- name: DeleteChirp :exec
+DELETE FROM chirps
+WHERE id = $1
+`
+
+func (q *Queries) DeleteChirp(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteChirp, id)
+	return err
+}
+
+const getChirpsByAuthorAsc = `-- name: GetChirpsByAuthorAsc :many
+SELECT id, created_at, updated_at, body, user_id, short_id, masked_ranges FROM chirps
+WHERE user_id = $1
+ORDER BY created_at, id
+`
+
+func (q *Queries) GetChirpsByAuthorAsc(ctx context.Context, userID uuid.UUID) ([]Chirp, error) {
+	rows, err := q.db.QueryContext(ctx, getChirpsByAuthorAsc, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Chirp
+	for rows.Next() {
+		var i Chirp
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Body,
+			&i.UserID,
+			&i.ShortID,
+			&i.MaskedRanges,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getChirpsByAuthorDesc = `-- name: GetChirpsByAuthorDesc :many
+SELECT id, created_at, updated_at, body, user_id, short_id, masked_ranges FROM chirps
+WHERE user_id = $1
+ORDER BY created_at DESC, id DESC
+`
+
+func (q *Queries) GetChirpsByAuthorDesc(ctx context.Context, userID uuid.UUID) ([]Chirp, error) {
+	rows, err := q.db.QueryContext(ctx, getChirpsByAuthorDesc, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Chirp
+	for rows.Next() {
+		var i Chirp
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Body,
+			&i.UserID,
+			&i.ShortID,
+			&i.MaskedRanges,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getChirpsPageAsc = `-- name: GetChirpsPageAsc :many
+SELECT id, created_at, updated_at, body, user_id, short_id, masked_ranges FROM chirps
+WHERE (created_at, id) > ($1::timestamp, $2::uuid)
+  AND ($3::uuid IS NULL OR user_id = $3)
+  AND ($4::uuid IS NULL OR EXISTS (
+    SELECT 1 FROM chirp_likes WHERE chirp_likes.chirp_id = chirps.id AND chirp_likes.user_id = $4
+  ))
+ORDER BY created_at, id
+LIMIT $5
+`
+
+type GetChirpsPageAscParams struct {
+	CursorCreatedAt time.Time
+	CursorID        uuid.UUID
+	AuthorID        uuid.NullUUID
+	LikedBy         uuid.NullUUID
+	PageLimit       int32
+}
+
+func (q *Queries) GetChirpsPageAsc(ctx context.Context, arg GetChirpsPageAscParams) ([]Chirp, error) {
+	rows, err := q.db.QueryContext(ctx, getChirpsPageAsc,
+		arg.CursorCreatedAt,
+		arg.CursorID,
+		arg.AuthorID,
+		arg.LikedBy,
+		arg.PageLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Chirp
+	for rows.Next() {
+		var i Chirp
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Body,
+			&i.UserID,
+			&i.ShortID,
+			&i.MaskedRanges,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getChirpsPageDesc = `-- name: GetChirpsPageDesc :many
+SELECT id, created_at, updated_at, body, user_id, short_id, masked_ranges FROM chirps
+WHERE (created_at, id) < ($1::timestamp, $2::uuid)
+  AND ($3::uuid IS NULL OR user_id = $3)
+  AND ($4::uuid IS NULL OR EXISTS (
+    SELECT 1 FROM chirp_likes WHERE chirp_likes.chirp_id = chirps.id AND chirp_likes.user_id = $4
+  ))
+ORDER BY created_at DESC, id DESC
+LIMIT $5
+`
+
+type GetChirpsPageDescParams struct {
+	CursorCreatedAt time.Time
+	CursorID        uuid.UUID
+	AuthorID        uuid.NullUUID
+	LikedBy         uuid.NullUUID
+	PageLimit       int32
+}
+
+func (q *Queries) GetChirpsPageDesc(ctx context.Context, arg GetChirpsPageDescParams) ([]Chirp, error) {
+	rows, err := q.db.QueryContext(ctx, getChirpsPageDesc,
+		arg.CursorCreatedAt,
+		arg.CursorID,
+		arg.AuthorID,
+		arg.LikedBy,
+		arg.PageLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Chirp
+	for rows.Next() {
+		var i Chirp
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Body,
+			&i.UserID,
+			&i.ShortID,
+			&i.MaskedRanges,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getChirpsLikedByAsc = `-- name: GetChirpsLikedByAsc :many
+SELECT id, created_at, updated_at, body, user_id, short_id, masked_ranges FROM chirps
+WHERE EXISTS (
+    SELECT 1 FROM chirp_likes WHERE chirp_likes.chirp_id = chirps.id AND chirp_likes.user_id = $1
+  )
+  AND ($2::uuid IS NULL OR user_id = $2)
+ORDER BY created_at, id
+`
+
+type GetChirpsLikedByAscParams struct {
+	LikedBy  uuid.UUID
+	AuthorID uuid.NullUUID
+}
+
+func (q *Queries) GetChirpsLikedByAsc(ctx context.Context, arg GetChirpsLikedByAscParams) ([]Chirp, error) {
+	rows, err := q.db.QueryContext(ctx, getChirpsLikedByAsc, arg.LikedBy, arg.AuthorID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Chirp
+	for rows.Next() {
+		var i Chirp
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Body,
+			&i.UserID,
+			&i.ShortID,
+			&i.MaskedRanges,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getChirpsLikedByDesc = `-- name: GetChirpsLikedByDesc :many
+SELECT id, created_at, updated_at, body, user_id, short_id, masked_ranges FROM chirps
+WHERE EXISTS (
+    SELECT 1 FROM chirp_likes WHERE chirp_likes.chirp_id = chirps.id AND chirp_likes.user_id = $1
+  )
+  AND ($2::uuid IS NULL OR user_id = $2)
+ORDER BY created_at DESC, id DESC
+`
+
+type GetChirpsLikedByDescParams struct {
+	LikedBy  uuid.UUID
+	AuthorID uuid.NullUUID
+}
+
+func (q *Queries) GetChirpsLikedByDesc(ctx context.Context, arg GetChirpsLikedByDescParams) ([]Chirp, error) {
+	rows, err := q.db.QueryContext(ctx, getChirpsLikedByDesc, arg.LikedBy, arg.AuthorID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Chirp
+	for rows.Next() {
+		var i Chirp
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Body,
+			&i.UserID,
+			&i.ShortID,
+			&i.MaskedRanges,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const updateChirp = `-- name: UpdateChirp :one
+UPDATE chirps SET body = $2, masked_ranges = $3, updated_at = $4
+WHERE id = $1
+RETURNING id, created_at, updated_at, body, user_id, short_id, masked_ranges
+`
+
+type UpdateChirpParams struct {
+	ID           uuid.UUID
+	Body         string
+	MaskedRanges json.RawMessage
+	UpdatedAt    time.Time
+}
+
+func (q *Queries) UpdateChirp(ctx context.Context, arg UpdateChirpParams) (Chirp, error) {
+	row := q.db.QueryRowContext(ctx, updateChirp,
+		arg.ID,
+		arg.Body,
+		arg.MaskedRanges,
+		arg.UpdatedAt,
+	)
+	var i Chirp
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Body,
+		&i.UserID,
+		&i.ShortID,
+		&i.MaskedRanges,
 	)
 	return i, err
 }
@@ -0,0 +1,45 @@
+package database
+
+// Index ist ein Index, den eine Abfrage für eine akzeptable Laufzeit braucht.
+type Index struct {
+	Feature string // Abfrage bzw. Filter, der den Index nutzt
+	Name    string
+	Create  string   // Anweisung zum Anlegen, ohne CONCURRENTLY
+	Queries []string // Namen der sqlc-Queries, die den Index nutzen (geprüft in indexes_test.go)
+}
+
+// RequiredIndexes listet die Indizes für die schweren Listen-Abfragen in chirp.sql,
+// chirp_likes.sql und follows.sql. Wer eine Abfrage mit neuem Filter oder neuer
+// Sortierung hinzufügt, trägt hier den passenden Index ein.
+var RequiredIndexes = []Index{
+	{
+		Feature: "GET /api/chirps (GetChirpsAsc/Desc, GetChirpsPageAsc/Desc: ORDER BY created_at, id)",
+		Name:    "chirps_created_at_id_idx",
+		Create:  "CREATE INDEX IF NOT EXISTS chirps_created_at_id_idx ON chirps (created_at, id)",
+		Queries: []string{
+			"GetChirpsAsc", "GetChirpsDesc",
+			"GetChirpsPageAsc", "GetChirpsPageDesc",
+			"GetChirpsLikedByAsc", "GetChirpsLikedByDesc",
+		},
+	},
+	{
+		Feature: "GET /api/chirps?author_id= (GetChirpsByAuthorAsc/Desc, GetChirpsPageAsc/Desc), GET /api/feed (GetFeedPage)",
+		Name:    "chirps_user_id_created_at_id_idx",
+		Create:  "CREATE INDEX IF NOT EXISTS chirps_user_id_created_at_id_idx ON chirps (user_id, created_at, id)",
+		Queries: []string{
+			"GetChirpsByAuthorAsc", "GetChirpsByAuthorDesc",
+			"GetChirpsPageAsc", "GetChirpsPageDesc",
+			"GetFeedPage",
+		},
+	},
+	{
+		Feature: "GET /api/chirps?liked_by= (GetChirpsLikedByAsc/Desc, GetChirpsPageAsc/Desc, GetChirpsLikedByUser)",
+		Name:    "chirp_likes_user_id_chirp_id_idx",
+		Create:  "CREATE INDEX IF NOT EXISTS chirp_likes_user_id_chirp_id_idx ON chirp_likes (user_id, chirp_id)",
+		Queries: []string{
+			"GetChirpsLikedByAsc", "GetChirpsLikedByDesc",
+			"GetChirpsPageAsc", "GetChirpsPageDesc",
+			"GetChirpsLikedByUser",
+		},
+	},
+}
@@ -0,0 +1,174 @@
+package database
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// Dateien mit den schweren Listen-Abfragen, siehe RequiredIndexes
+var heavyQueryFiles = []string{"002_chirp.sql", "006_chirp_likes.sql", "007_follows.sql"}
+
+// :many-Queries, die schon der Primärschlüssel abdeckt
+var primaryKeyQueries = map[string]string{
+	"GetChirpLikeCounts": "chirp_likes_pkey (chirp_id, user_id)",
+}
+
+var (
+	queryHeaderPattern = regexp.MustCompile(`(?m)^-- name: (\w+) :(\w+)$`)
+	orderByPattern     = regexp.MustCompile(`(?is)\bORDER BY\s+(.*?)(?:\s+LIMIT\b|;|$)`)
+	indexColsPattern   = regexp.MustCompile(`\bON (\w+) \(([^)]+)\)`)
+	// Keyset-Vergleich (created_at, id) < (...) innerhalb einer OR-Verzweigung
+	orKeysetPattern = regexp.MustCompile(`(?i)\bOR\s*\(?\s*\(\s*[\w.]*created_at\s*,`)
+)
+
+type namedQuery struct {
+	kind string
+	sql  string
+}
+
+func readHeavyQueries(t *testing.T) map[string]namedQuery {
+	t.Helper()
+	queries := map[string]namedQuery{}
+	for _, file := range heavyQueryFiles {
+		data, err := os.ReadFile(filepath.Join("..", "..", "sql", "migrations", file))
+		if err != nil {
+			t.Fatal(err)
+		}
+		text := string(data)
+		headers := queryHeaderPattern.FindAllStringSubmatchIndex(text, -1)
+		for i, h := range headers {
+			end := len(text)
+			if i+1 < len(headers) {
+				end = headers[i+1][0]
+			}
+			name, kind := text[h[2]:h[3]], text[h[4]:h[5]]
+			queries[name] = namedQuery{kind: kind, sql: strings.TrimSpace(text[h[1]:end])}
+		}
+	}
+	return queries
+}
+
+// Spalten eines Index aus seiner CREATE-Anweisung.
+func indexColumns(t *testing.T, idx Index) (table string, cols []string) {
+	t.Helper()
+	m := indexColsPattern.FindStringSubmatch(idx.Create)
+	if m == nil {
+		t.Fatalf("can't parse columns of %s from %q", idx.Name, idx.Create)
+	}
+	for _, col := range strings.Split(m[2], ",") {
+		cols = append(cols, strings.TrimSpace(col))
+	}
+	return m[1], cols
+}
+
+// Sortierspalten ohne Tabellen-Präfix; ok ist false bei Ausdrücken oder gemischter Richtung.
+func orderColumns(orderBy string) (cols []string, ok bool) {
+	direction := ""
+	for _, term := range strings.Split(orderBy, ",") {
+		fields := strings.Fields(term)
+		if len(fields) == 0 || len(fields) > 2 || !regexp.MustCompile(`^[\w.]+$`).MatchString(fields[0]) {
+			return nil, false
+		}
+		dir := "ASC"
+		if len(fields) == 2 {
+			dir = strings.ToUpper(fields[1])
+		}
+		if direction != "" && dir != direction {
+			return nil, false
+		}
+		direction = dir
+		col := fields[0]
+		if _, after, found := strings.Cut(col, "."); found {
+			col = after
+		}
+		cols = append(cols, col)
+	}
+	return cols, true
+}
+
+// Meldet, ob die Query die Spalte per Gleichheit einschränkt (Filter oder Join).
+func hasEqualityOn(sql, col string) bool {
+	return regexp.MustCompile(`(?i)(\b` + col + `\s*=|=\s*\w+\.` + col + `\b)`).MatchString(sql)
+}
+
+func TestRequiredIndexesCoverHeavyQueries(t *testing.T) {
+	queries := readHeavyQueries(t)
+	if len(queries) == 0 {
+		t.Fatal("no queries found in the migration files")
+	}
+	indexesByQuery := map[string][]Index{}
+	for _, idx := range RequiredIndexes {
+		for _, name := range idx.Queries {
+			if _, ok := queries[name]; !ok {
+				t.Errorf("index %s lists unknown query %s", idx.Name, name)
+			}
+			indexesByQuery[name] = append(indexesByQuery[name], idx)
+		}
+	}
+
+	for name, q := range queries {
+		if q.kind != "many" {
+			continue
+		}
+		if _, ok := primaryKeyQueries[name]; ok {
+			continue
+		}
+		indexes := indexesByQuery[name]
+		if len(indexes) == 0 {
+			t.Errorf("%s is a list query but no entry in RequiredIndexes covers it", name)
+			continue
+		}
+		if orKeysetPattern.MatchString(q.sql) {
+			t.Errorf("%s compares the keyset inside an OR, which prevents an index range scan", name)
+		}
+
+		m := orderByPattern.FindStringSubmatch(q.sql)
+		if m == nil {
+			continue
+		}
+		orderBy := strings.Join(strings.Fields(m[1]), " ")
+		cols, ok := orderColumns(orderBy)
+		if !ok {
+			t.Errorf("%s: ORDER BY %q must be plain columns in one direction to use an index", name, orderBy)
+			continue
+		}
+		covered := slices.ContainsFunc(indexes, func(idx Index) bool {
+			_, idxCols := indexColumns(t, idx)
+			if len(idxCols) < len(cols) || !slices.Equal(idxCols[len(idxCols)-len(cols):], cols) {
+				return false
+			}
+			for _, prefix := range idxCols[:len(idxCols)-len(cols)] {
+				if !hasEqualityOn(q.sql, prefix) {
+					return false
+				}
+			}
+			return true
+		})
+		if !covered {
+			t.Errorf("%s: no listed index serves ORDER BY %s", name, orderBy)
+		}
+	}
+}
+
+func TestOrderColumns(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []string
+		ok   bool
+	}{
+		{"created_at, id", []string{"created_at", "id"}, true},
+		{"chirps.created_at DESC, chirps.id DESC", []string{"created_at", "id"}, true},
+		{"created_at DESC, id", nil, false},
+		{"CASE WHEN $1::text = 'desc' THEN created_at END DESC, created_at ASC", nil, false},
+	}
+	for _, tt := range tests {
+		got, ok := orderColumns(tt.in)
+		if ok != tt.ok || !slices.Equal(got, tt.want) {
+			t.Errorf("orderColumns(%q) = %v, %v; want %v, %v", tt.in, got, ok, tt.want, tt.ok)
+		}
+	}
+}
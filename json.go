@@ -0,0 +1,76 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"sync/atomic"
+	"syscall"
+
+	"github.com/nuke87/go_http_server/pkg/chirpy"
+)
+
+// Zähler für Fehler beim Schreiben von Antworten, getrennt nach Ursache.
+var (
+	responseClientAborts  atomic.Int64 // Client hat die Verbindung während der Antwort getrennt
+	responseMarshalErrors atomic.Int64 // Payload ließ sich nicht als JSON serialisieren
+)
+
+// Aktiviert Debug-Logs (LOG_LEVEL=debug).
+var debugLogging bool
+
+func respondWithError(w http.ResponseWriter, code int, msg string, err error) {
+	respondWithErrorCode(w, code, "", msg, err)
+}
+
+// Wie respondWithError, zusätzlich mit maschinenlesbarem Fehlercode im Feld "code".
+func respondWithErrorCode(w http.ResponseWriter, code int, errCode, msg string, err error) {
+	if err != nil {
+		log.Println(err)
+	}
+	if code > 499 {
+		log.Printf("Responding with 5XX error: %s", msg)
+	}
+	respondWithJSON(w, code, chirpy.ErrorResponse{
+		Error: msg,
+		Code:  errCode,
+	})
+}
+
+func respondWithJSON(w http.ResponseWriter, code int, payload interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	dat, err := json.Marshal(payload)
+	if err != nil {
+		// Es wurde noch nichts geschrieben, daher kann noch ein 500 gesendet werden.
+		responseMarshalErrors.Add(1)
+		log.Printf("Error marshalling JSON: %s", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(code)
+	if _, err := w.Write(dat); err != nil {
+		handleWriteError(err)
+	}
+}
+
+// Wertet einen Fehler beim Schreiben des Bodys aus. Ein Abbruch durch den Client ist
+// kein Serverfehler und wird nur im Debug-Log vermerkt.
+func handleWriteError(err error) {
+	if isClientAbort(err) {
+		responseClientAborts.Add(1)
+		if debugLogging {
+			log.Printf("debug: client went away while writing response: %s", err)
+		}
+		return
+	}
+	log.Printf("Error writing response: %s", err)
+}
+
+func isClientAbort(err error) bool {
+	return errors.Is(err, context.Canceled) ||
+		errors.Is(err, syscall.EPIPE) ||
+		errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, http.ErrHandlerTimeout)
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"strings"
+	"syscall"
+	"testing"
+)
+
+// failingWriter nimmt limit Bytes an und liefert danach err.
+type failingWriter struct {
+	header  http.Header
+	status  int
+	written strings.Builder
+	limit   int
+	err     error
+}
+
+func newFailingWriter(limit int, err error) *failingWriter {
+	return &failingWriter{header: http.Header{}, limit: limit, err: err}
+}
+
+func (w *failingWriter) Header() http.Header { return w.header }
+
+func (w *failingWriter) WriteHeader(code int) {
+	if w.status == 0 {
+		w.status = code
+	}
+}
+
+func (w *failingWriter) Write(p []byte) (int, error) {
+	w.WriteHeader(http.StatusOK)
+	room := w.limit - w.written.Len()
+	if len(p) <= room {
+		w.written.Write(p)
+		return len(p), nil
+	}
+	w.written.Write(p[:max(room, 0)])
+	return max(room, 0), w.err
+}
+
+func TestRespondWithJSONWriteErrors(t *testing.T) {
+	payload := map[string]string{"body": strings.Repeat("x", 100)}
+	tests := []struct {
+		name        string
+		limit       int
+		err         error
+		wantAborts  int64
+		wantWritten int
+	}{
+		{"broken pipe after 10 bytes", 10, fmt.Errorf("write tcp: %w", syscall.EPIPE), 1, 10},
+		{"connection reset before any byte", 0, fmt.Errorf("write tcp: %w", syscall.ECONNRESET), 1, 0},
+		{"context canceled", 50, context.Canceled, 1, 50},
+		{"handler timeout", 5, http.ErrHandlerTimeout, 1, 5},
+		{"other write error", 10, errors.New("disk full"), 0, 10},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			aborts, marshalErrors := responseClientAborts.Load(), responseMarshalErrors.Load()
+			w := newFailingWriter(tt.limit, tt.err)
+			respondWithJSON(w, http.StatusCreated, payload)
+
+			// Status und Header sind schon raus; es wird nichts nachgeschoben
+			if w.status != http.StatusCreated {
+				t.Errorf("status = %d, want 201", w.status)
+			}
+			if w.written.Len() != tt.wantWritten {
+				t.Errorf("wrote %d bytes, want %d", w.written.Len(), tt.wantWritten)
+			}
+			if got := responseClientAborts.Load() - aborts; got != tt.wantAborts {
+				t.Errorf("client aborts += %d, want %d", got, tt.wantAborts)
+			}
+			if got := responseMarshalErrors.Load() - marshalErrors; got != 0 {
+				t.Errorf("marshal errors += %d, want 0", got)
+			}
+		})
+	}
+}
+
+func TestRespondWithJSONMarshalError(t *testing.T) {
+	for name, payload := range map[string]any{
+		"NaN":     map[string]float64{"score": math.NaN()},
+		"channel": map[string]any{"c": make(chan int)},
+	} {
+		t.Run(name, func(t *testing.T) {
+			aborts, marshalErrors := responseClientAborts.Load(), responseMarshalErrors.Load()
+			w := newFailingWriter(1<<20, nil)
+			respondWithJSON(w, http.StatusOK, payload)
+
+			if w.status != http.StatusInternalServerError {
+				t.Errorf("status = %d, want 500", w.status)
+			}
+			if w.written.Len() != 0 {
+				t.Errorf("wrote %q after a marshal error", w.written.String())
+			}
+			if got := responseMarshalErrors.Load() - marshalErrors; got != 1 {
+				t.Errorf("marshal errors += %d, want 1", got)
+			}
+			if got := responseClientAborts.Load() - aborts; got != 0 {
+				t.Errorf("client aborts += %d, want 0", got)
+			}
+		})
+	}
+}
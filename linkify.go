@@ -0,0 +1,54 @@
+package main
+
+import (
+	"html"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// Kandidaten für Links; Satzzeichen am Ende schneidet trimURL wieder ab.
+var linkifyURLPattern = regexp.MustCompile(`https?://[^\s<>"']+`)
+
+// linkify wandelt bereinigten Klartext (Bio, Chirp) in HTML um: Alles wird escaped,
+// http- und https-URLs werden zu Links mit rel="nofollow noopener ugc", Zeilenumbrüche zu <br>.
+// Das Ergebnis darf unverändert in HTML-Seiten eingesetzt werden. @-Erwähnungen werden
+// nicht verlinkt, weil User keine eindeutigen Handles haben.
+func linkify(text string) string {
+	var b strings.Builder
+	last := 0
+	for _, m := range linkifyURLPattern.FindAllStringIndex(text, -1) {
+		raw := trimURL(text[m[0]:m[1]])
+		u, err := url.Parse(raw)
+		if err != nil || u.Host == "" {
+			continue
+		}
+		b.WriteString(escapeLines(text[last:m[0]]))
+		b.WriteString(`<a href="` + html.EscapeString(u.String()) + `" rel="nofollow noopener ugc">`)
+		b.WriteString(html.EscapeString(raw))
+		b.WriteString(`</a>`)
+		last = m[0] + len(raw)
+	}
+	b.WriteString(escapeLines(text[last:]))
+	return b.String()
+}
+
+// Entfernt Satzzeichen am Ende, die eher zum Satz als zur URL gehören. Eine schließende
+// Klammer bleibt, wenn die URL selbst eine öffnende enthält (Wikipedia-Links).
+func trimURL(raw string) string {
+	for raw != "" {
+		last := raw[len(raw)-1]
+		switch {
+		case strings.IndexByte(".,;:!?", last) >= 0:
+		case last == ')' && strings.Count(raw, "(") < strings.Count(raw, ")"):
+		default:
+			return raw
+		}
+		raw = raw[:len(raw)-1]
+	}
+	return raw
+}
+
+func escapeLines(s string) string {
+	return strings.ReplaceAll(html.EscapeString(s), "\n", "<br>\n")
+}
@@ -0,0 +1,36 @@
+package main
+
+import "testing"
+
+func TestLinkify(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"plain text", "hello world", "hello world"},
+		{"escapes markup", `<b>"hi"</b> & 'you'`, "&lt;b&gt;&#34;hi&#34;&lt;/b&gt; &amp; &#39;you&#39;"},
+		{"script tag", "<script>alert(1)</script>", "&lt;script&gt;alert(1)&lt;/script&gt;"},
+		{"https url", "see https://example.com/a?b=1&c=2 now",
+			`see <a href="https://example.com/a?b=1&amp;c=2" rel="nofollow noopener ugc">https://example.com/a?b=1&amp;c=2</a> now`},
+		{"trailing punctuation", "visit http://example.com.",
+			`visit <a href="http://example.com" rel="nofollow noopener ugc">http://example.com</a>.`},
+		{"url in parentheses", "(https://example.com/x)",
+			`(<a href="https://example.com/x" rel="nofollow noopener ugc">https://example.com/x</a>)`},
+		{"parentheses inside url", "https://en.wikipedia.org/wiki/Go_(programming_language)",
+			`<a href="https://en.wikipedia.org/wiki/Go_(programming_language)" rel="nofollow noopener ugc">https://en.wikipedia.org/wiki/Go_(programming_language)</a>`},
+		{"quote ends url", `"https://example.com"onmouseover=x`,
+			`&#34;<a href="https://example.com" rel="nofollow noopener ugc">https://example.com</a>&#34;onmouseover=x`},
+		{"javascript scheme stays text", "javascript:alert(1)", "javascript:alert(1)"},
+		{"scheme without host", "https:// nothing", "https:// nothing"},
+		{"mentions stay text", "hi @alice", "hi @alice"},
+		{"newlines", "line one\nline two", "line one<br>\nline two"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := linkify(tt.in); got != tt.want {
+				t.Errorf("linkify(%q)\n got %s\nwant %s", tt.in, got, tt.want)
+			}
+		})
+	}
+}
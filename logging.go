@@ -0,0 +1,72 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// Liest LOG_LEVEL (debug, info, warn, error); leer bedeutet info.
+func logLevelFromEnv(raw string) (slog.Level, error) {
+	switch strings.ToLower(raw) {
+	case "", "info":
+		return slog.LevelInfo, nil
+	case "debug":
+		return slog.LevelDebug, nil
+	case "warn", "warning":
+		return slog.LevelWarn, nil
+	case "error":
+		return slog.LevelError, nil
+	}
+	return slog.LevelInfo, fmt.Errorf("LOG_LEVEL must be debug, info, warn or error, got %q", raw)
+}
+
+// Setzt einen JSON-Logger auf stdout als Standard für slog (und damit auch für log).
+func setupLogging(level slog.Level) {
+	slog.SetDefault(slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{Level: level})))
+	debugLogging = level <= slog.LevelDebug
+}
+
+// Middleware, die jeden Request mit Methode, Pfad, Status, Antwortgröße, Dauer und
+// Remote-Adresse strukturiert loggt. 5xx-Antworten werden als Error geloggt, Routen
+// ohne Request-Metriken (siehe middlewareSkipRequestMetrics) nur auf Debug-Level.
+func (cfg *apiConfig) middlewareLogging(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		entry := &logEntry{}
+		sw := &statusWriter{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(sw, r.WithContext(context.WithValue(r.Context(), logEntryKey{}, entry)))
+
+		level := slog.LevelInfo
+		switch {
+		case sw.status >= 500:
+			level = slog.LevelError
+		case entry.debugOnly:
+			level = slog.LevelDebug
+		}
+		slog.Log(r.Context(), level, "request",
+			"method", r.Method,
+			"path", r.URL.Path,
+			"status", sw.status,
+			"bytes", sw.bytes,
+			"duration_ms", float64(time.Since(start).Microseconds())/1000,
+			"remote_addr", r.RemoteAddr,
+		)
+	})
+}
+
+// Kontextwert, über den Handler das Log des umgebenden Requests beeinflussen.
+type logEntryKey struct{}
+
+type logEntry struct {
+	debugOnly bool
+}
+
+func logEntryFromContext(ctx context.Context) *logEntry {
+	entry, _ := ctx.Value(logEntryKey{}).(*logEntry)
+	return entry
+}
@@ -3,239 +3,448 @@ package main
 import (
 	"database/sql"
 	"encoding/json"
-	"fmt"
 	"log"
+	"log/slog"
 	"net/http"
 	"os"
+	"strconv"
 	"strings"
 	"sync/atomic"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/nuke87/go_http_server/internal/auth"
 	"github.com/nuke87/go_http_server/internal/database"
+	"github.com/nuke87/go_http_server/pkg/chirpy"
 
 	"github.com/joho/godotenv"
 	_ "github.com/lib/pq"
 )
 
 type apiConfig struct {
-	fileserverHits atomic.Int32
-	db             *database.Queries
-	platform       string
+	fileserverHits    atomic.Int32
+	fileserverBotHits atomic.Int32
+	botMatcher        atomic.Pointer[botMatcher]
+	skipNotModified   bool
+	db                Store  // Datenhaltung (STORAGE): postgresStore oder memoryStore
+	dbPinger          Pinger // Für /api/readyz, im Betrieb die *sql.DB
+	platform          string
+	baseURL           string
+	basePath          string
+	stripDiacritics   bool
+	routeTable        []route
+	signupChallenge   SignupChallenge
+	translator        Translator
+	translateLangs    []string // Erlaubte Zielsprachen (TRANSLATE_LANGUAGES), Basissprache ohne Region
+	clock             clock
+	jwtSecret         string
+	jwtExpiresIn      time.Duration
+	editWindow        time.Duration // Wie lange ein Chirp nach dem Anlegen bearbeitet werden darf, 0 = unbegrenzt
+	bidiPolicy        string
+	polkaKey          string // API-Key für Webhooks von Polka
+	adminToken        string // Bearer-Token für Admin-Routen außerhalb von PLATFORM=dev (ADMIN_TOKEN)
+	adminUserIDs      map[uuid.UUID]bool
+	bannedWords       []string // Wortliste des Profanity-Filters (BANNED_WORDS, BANNED_WORDS_FILE)
+	startedAt         time.Time
+	requestMetrics    *requestMetrics
+	metricsExclude    map[string]bool         // Zusätzlich von den Request-Zählern ausgenommene Routen (METRICS_EXCLUDE)
+	rateLimiters      map[string]*rateLimiter // Nach routeOptions.RateLimit, nil wenn abgeschaltet
 }
 
 func main() {
-	const filepathRoot = "."
-	const port = "8080"
-
+	processEnv := environSnapshot() // Vor der .env-Datei, für den Reload per SIGHUP
 	godotenv.Load()
+	serverCfg, err := loadServerConfig(os.Getenv)
+	if err != nil {
+		log.Fatalf("Invalid server config: %s", err)
+	}
+	storage, err := parseStorage(os.Getenv("STORAGE"))
+	if err != nil {
+		log.Fatal(err)
+	}
 	dbURL := os.Getenv("DB_URL")
-	if dbURL == "" {
+	if storage == storagePostgres && dbURL == "" {
 		log.Fatal("DB_URL must be set")
 	}
 	platform := os.Getenv("PLATFORM")
-
-	dbConn, err := sql.Open("postgres", dbURL)
+	polkaKey := os.Getenv("POLKA_KEY")
+	jwtSecret := os.Getenv("JWT_SECRET")
+	if jwtSecret == "" {
+		log.Fatal("JWT_SECRET must be set")
+	}
+	bidiPolicy := os.Getenv("CHIRP_BIDI_CONTROLS")
+	if bidiPolicy == "" {
+		bidiPolicy = bidiStrip
+	}
+	if bidiPolicy != bidiStrip && bidiPolicy != bidiReject {
+		log.Fatalf("CHIRP_BIDI_CONTROLS must be %q or %q, got %q", bidiStrip, bidiReject, bidiPolicy)
+	}
+	jwtExpiresIn := time.Hour
+	if raw := os.Getenv("JWT_EXPIRES_IN"); raw != "" {
+		d, err := time.ParseDuration(raw)
+		if err != nil || d <= 0 {
+			log.Fatalf("JWT_EXPIRES_IN must be a positive duration like 1h, got %q", raw)
+		}
+		jwtExpiresIn = d
+	}
+	editWindow := defaultChirpEditWindow
+	if raw := os.Getenv("CHIRP_EDIT_WINDOW"); raw != "" {
+		d, err := time.ParseDuration(raw)
+		if err != nil || d < 0 {
+			log.Fatalf("CHIRP_EDIT_WINDOW must be a duration like 30m, or 0 for unlimited, got %q", raw)
+		}
+		editWindow = d
+	}
+	logLevel, err := logLevelFromEnv(os.Getenv("LOG_LEVEL"))
 	if err != nil {
-		log.Fatalf("Error opening database: %s", err)
+		log.Fatal(err)
+	}
+	setupLogging(logLevel)
+	baseURL := strings.TrimSuffix(os.Getenv("BASE_URL"), "/")
+	if baseURL == "" {
+		baseURL = "http://localhost:" + serverCfg.Port
+	}
+	if raw := os.Getenv("MAX_REQUEST_BODY_BYTES"); raw != "" {
+		limit, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil || limit <= 0 {
+			log.Fatalf("MAX_REQUEST_BODY_BYTES must be a positive number of bytes, got %q", raw)
+		}
+		maxRequestBodyBytes = limit
+	}
+	basePath, err := normalizeBasePath(os.Getenv("BASE_PATH"))
+	if err != nil {
+		log.Fatalf("Invalid BASE_PATH: %s", err)
+	}
+
+	// Ohne Postgres bleibt dbConn nil; Migrations- und Index-Prüfung entfallen dann.
+	var dbConn *sql.DB
+	var store Store
+	var pinger Pinger
+	if storage == storageMemory {
+		memStore := newMemoryStore()
+		store, pinger = memStore, memStore
+	} else {
+		dbConn, err = sql.Open("postgres", dbURL)
+		if err != nil {
+			log.Fatalf("Error opening database: %s", err)
+		}
+		store, pinger = newPostgresStore(dbConn), dbConn
 	}
-	dbQueries := database.New(dbConn)
 
 	apiCfg := apiConfig{
-		fileserverHits: atomic.Int32{},
-		db:             dbQueries,
-		platform:       platform,
+		fileserverHits:  atomic.Int32{},
+		skipNotModified: os.Getenv("METRICS_SKIP_NOT_MODIFIED") == "true",
+		db:              store,
+		dbPinger:        pinger,
+		platform:        platform,
+		baseURL:         baseURL,
+		basePath:        basePath,
+		stripDiacritics: os.Getenv("PROFANITY_STRIP_DIACRITICS") != "false",
+		translator:      translatorFromEnv(),
+		jwtSecret:       jwtSecret,
+		polkaKey:        polkaKey,
+		startedAt:       time.Now(),
+		requestMetrics:  newRequestMetrics(),
+		metricsExclude:  metricsExcludeFromEnv(os.Getenv("METRICS_EXCLUDE")),
+		jwtExpiresIn:    jwtExpiresIn,
+		editWindow:      editWindow,
+		bidiPolicy:      bidiPolicy,
+	}
+	apiCfg.botMatcher.Store(botMatcherFromEnv(os.LookupEnv))
+	apiCfg.signupChallenge, err = signupChallengeFromEnv(apiCfg.clock.Now)
+	if err != nil {
+		log.Fatalf("Invalid signup challenge config: %s", err)
+	}
+	apiCfg.bannedWords, err = bannedWordsFromEnv()
+	if err != nil {
+		log.Fatalf("Invalid banned words config: %s", err)
+	}
+	apiCfg.rateLimiters, err = rateLimitersFromEnv()
+	if err != nil {
+		log.Fatalf("Invalid rate limit config: %s", err)
 	}
+	apiCfg.translateLangs, err = translateLanguagesFromEnv(os.Getenv("TRANSLATE_LANGUAGES"))
+	if err != nil {
+		log.Fatalf("Invalid translation config: %s", err)
+	}
+	apiCfg.adminToken = os.Getenv("ADMIN_TOKEN")
+	apiCfg.adminUserIDs, err = adminUserIDsFromEnv(os.Getenv("ADMIN_USER_IDS"))
+	if err != nil {
+		log.Fatalf("Invalid admin config: %s", err)
+	}
+	go apiCfg.watchReload(processEnv)
 
 	mux := http.NewServeMux()
-	fsHandler := apiCfg.middlewareMetricsInc(http.StripPrefix("/app", http.FileServer(http.Dir(filepathRoot))))
-	mux.Handle("/app/", fsHandler)
-
-	mux.HandleFunc("GET /api/healthz", handlerReadiness)
 	//mux.HandleFunc("POST /api/validate_chirp", handlerChirpsValidate)
-	mux.HandleFunc("POST /api/users", apiCfg.handlerCreateUser)
-	mux.HandleFunc("POST /admin/reset", apiCfg.handlerReset)
-	mux.HandleFunc("GET /admin/metrics", apiCfg.handlerMetrics)
-	mux.HandleFunc("POST /api/chirps", apiCfg.handlerCreateChirp)
-
-	srv := &http.Server{
-		Addr:    ":" + port,
-		Handler: mux,
+	if err := apiCfg.registerRoutes(mux, apiCfg.routes(serverCfg.FilepathRoot)); err != nil {
+		log.Fatalf("Error registering routes: %s", err)
 	}
 
-	log.Printf("Serving on port: %s\n", port)
-	log.Fatal(srv.ListenAndServe())
-}
-
-// Middleware: Zählt Zugriffe auf /app/
-func (cfg *apiConfig) middlewareMetricsInc(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		cfg.fileserverHits.Add(1)
-		next.ServeHTTP(w, r)
-	})
-}
+	srv := serverCfg.newServer(apiCfg.serverHandler(mux))
 
-// Handler für /api/healthz
-func handlerReadiness(w http.ResponseWriter, r *http.Request) {
-	w.WriteHeader(http.StatusOK)
-	w.Write([]byte("OK"))
-}
-
-// Handler für /api/validate_chirp (Dummy)
-func handlerChirpsValidate(w http.ResponseWriter, r *http.Request) {
-	w.WriteHeader(http.StatusOK)
-	w.Write([]byte(`{"valid":true}`))
-}
+	info := startupInfo{
+		Version:      version,
+		Platform:     platform,
+		ListenAddr:   srv.Addr,
+		Storage:      storage,
+		Features:     apiCfg.enabledFeatures(),
+		AdminAuth:    apiCfg.adminAuthRequired(),
+		AuthRoutes:   apiCfg.hasAuthRoutes(),
+		JWTSecretSet: apiCfg.jwtSecret != "",
+		JWTProblems:  auth.CheckSecret(apiCfg.jwtSecret),
+	}
+	if err := auth.SelfCheck(apiCfg.jwtSecret, apiCfg.clock.Now()); err != nil {
+		info.JWTProblems = append(info.JWTProblems, "JWT self-check: "+err.Error())
+	}
+	var missing []database.Index
+	if dbConn != nil {
+		info.DBHost = dbHost(dbURL)
+		info.MigrationVersion = migrationVersion(dbConn)
+		missing, err = missingIndexes(dbConn)
+		if err != nil {
+			slog.Warn("couldn't check indexes", "error", err)
+		}
+		info.MissingIndexes = missing
+	}
+	logStartupBanner(info)
+	strict := os.Getenv("STRICT_STARTUP") == "true"
+	for _, warning := range startupWarnings(info) {
+		if strict {
+			log.Fatalf("STRICT_STARTUP: %s", warning)
+		}
+		slog.Warn(warning)
+	}
 
-// Handler für /admin/reset
-// Setzt den Zugriffszähler zurück und löscht (optional, siehe Aufgabe) alle User aus der Datenbank.
-// In dieser Version wird nur der Zähler zurückgesetzt.
-func (cfg *apiConfig) handlerReset(w http.ResponseWriter, r *http.Request) {
-	cfg.fileserverHits.Store(0)
-	w.WriteHeader(http.StatusOK)
-	w.Write([]byte("OK"))
-}
+	if len(missing) > 0 && os.Getenv("AUTO_CREATE_INDEXES") == "true" {
+		go createIndexes(dbConn, missing)
+	}
 
-// Handler für /admin/metrics
-// Gibt eine HTML-Seite mit der aktuellen Anzahl der Zugriffe auf /app/ zurück.
-func (cfg *apiConfig) handlerMetrics(w http.ResponseWriter, r *http.Request) {
-	w.Header().Set("Content-Type", "text/html; charset=utf-8")
-	w.WriteHeader(http.StatusOK)
-	w.Write([]byte(fmt.Sprintf(`<html>
-  <body>
-    <h1>Welcome, Chirpy Admin</h1>
-    <p>Chirpy has been visited %d times!</p>
-  </body>
-</html>`, cfg.fileserverHits.Load())))
+	log.Printf("Serving on port: %s\n", serverCfg.Port)
+	log.Fatal(srv.ListenAndServe())
 }
 
-// User struct für die JSON-Antwort
-type User struct {
-	ID        uuid.UUID `json:"id"`         // Eindeutige User-ID (UUID), wird als "id" im JSON ausgegeben
-	CreatedAt time.Time `json:"created_at"` // Erstellungszeitpunkt, wird als "created_at" im JSON ausgegeben
-	UpdatedAt time.Time `json:"updated_at"` // Zeitpunkt der letzten Änderung, wird als "updated_at" im JSON ausgegeben
-	Email     string    `json:"email"`      // E-Mail-Adresse des Users, wird als "email" im JSON ausgegeben
+// Liste der aktivierten optionalen Features für das Start-Banner
+func (cfg *apiConfig) enabledFeatures() []string {
+	features := []string{"bot_filter"}
+	if cfg.skipNotModified {
+		features = append(features, "metrics_skip_not_modified")
+	}
+	if cfg.stripDiacritics {
+		features = append(features, "profanity_strip_diacritics")
+	}
+	if cfg.signupChallenge != nil {
+		features = append(features, "signup_challenge")
+	}
+	return features
 }
 
 // Handler für /api/users (POST)
 func (cfg *apiConfig) handlerCreateUser(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPost { // Nur POST-Anfragen sind erlaubt
-		http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed) // Bei anderen Methoden: 405 zurückgeben
-		return
-	}
-
 	type requestBody struct {
-		Email string `json:"email"` // Erwartet ein Feld "email" im JSON-Request
+		Email    string  `json:"email"`    // Erwartet ein Feld "email" im JSON-Request
+		Password string  `json:"password"` // Klartext-Passwort, wird nur als bcrypt-Hash gespeichert
+		ID       *string `json:"id"`       // Nicht erlaubt: User-IDs vergibt immer der Server
+		signupProof
 	}
 	var req requestBody
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Email == "" { // JSON dekodieren und prüfen, ob E-Mail vorhanden ist
-		http.Error(w, `{"error":"invalid request"}`, http.StatusBadRequest) // Fehlerhafte Anfrage: 400 zurückgeben
+	if reqErr := decodeJSONBody(w, r, &req); reqErr != nil { // JSON dekodieren; leerer oder kaputter Body: 400
+		respondWithRequestError(w, reqErr)
+		return
+	}
+	if req.Email == "" { // Prüfen, ob E-Mail vorhanden ist
+		respondWithErrorCode(w, http.StatusBadRequest, errCodeMissingField, "email is required", nil)
+		return
+	}
+	email, reqErr := normalizeEmail(req.Email) // Format prüfen, Domain kleinschreiben
+	if reqErr != nil {
+		respondWithRequestError(w, reqErr)
+		return
+	}
+	if req.ID != nil { // Eigene IDs gibt es nur für Chirps
+		respondWithError(w, http.StatusBadRequest, "id cannot be set when creating a user", nil)
+		return
+	}
+	if req.Password == "" { // Leere oder fehlende Passwörter ablehnen
+		respondWithErrorCode(w, http.StatusBadRequest, errCodeMissingField, "password is required", nil)
 		return
 	}
+	if len(req.Password) > auth.MaxPasswordBytes { // bcrypt verarbeitet höchstens 72 Bytes
+		respondWithRequestError(w, &requestError{status: http.StatusBadRequest, code: errCodeTooLong, field: "password", msg: "password must be at most " + strconv.Itoa(auth.MaxPasswordBytes) + " bytes"})
+		return
+	}
+	created := false
+	if cfg.signupChallenge != nil { // Optionaler Bot-Schutz (SIGNUP_CHALLENGE)
+		if err := cfg.signupChallenge.Verify(r.Context(), req.signupProof, clientIP(r)); err != nil {
+			respondWithErrorCode(w, http.StatusForbidden, cfg.signupChallenge.ErrorCode(), "Signup challenge failed: "+err.Error(), nil)
+			return
+		}
+		// Scheitert das Anlegen, bleibt die Challenge für einen neuen Versuch gültig.
+		defer func() {
+			if !created {
+				cfg.signupChallenge.Release(req.signupProof)
+			}
+		}()
+	}
 
-	// Dummy-User anlegen (in echter App: DB nutzen)
-	now := time.Now().UTC() // Aktuelle Zeit in UTC holen
-	user := User{
-		ID:        uuid.New(), // Neue UUID generieren
-		CreatedAt: now,        // Erstellungszeitpunkt setzen
-		UpdatedAt: now,        // Aktualisierungszeitpunkt setzen
-		Email:     req.Email,  // E-Mail aus Request übernehmen
+	hashedPassword, err := auth.HashPassword(req.Password)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't hash password", err)
+		return
 	}
 
-	w.Header().Set("Content-Type", "application/json") // Antwort als JSON deklarieren
-	w.WriteHeader(http.StatusCreated)                  // HTTP-Status 201 Created setzen
-	json.NewEncoder(w).Encode(user)                    // User-Objekt als JSON zurückgeben
+	// User in der Datenbank anlegen
+	now := cfg.clock.Now().UTC() // Aktuelle Zeit in UTC holen
+	dbUser, err := cfg.db.CreateUser(r.Context(), database.CreateUserParams{
+		ID:             uuid.New(),     // Neue UUID generieren
+		CreatedAt:      now,            // Erstellungszeitpunkt setzen
+		UpdatedAt:      now,            // Aktualisierungszeitpunkt setzen
+		Email:          email,          // Normalisierte E-Mail speichern
+		HashedPassword: hashedPassword, // Nur den Hash speichern, nie das Passwort
+	})
+	if isUniqueViolation(err, "users_email_key") { // E-Mail bereits vergeben: 409 zurückgeben
+		respondWithError(w, http.StatusConflict, "A user with this email already exists", nil)
+		return
+	}
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't create user", err)
+		return
+	}
+
+	created = true
+	respondWithJSON(w, http.StatusCreated, userJSON(dbUser)) // Gespeicherten User mit 201 Created als JSON zurückgeben
+}
+
+// Wandelt einen User aus der DB in seine JSON-Darstellung um.
+func userJSON(u database.User) chirpy.User {
+	return chirpy.User{
+		ID:          u.ID,
+		CreatedAt:   u.CreatedAt,
+		UpdatedAt:   u.UpdatedAt,
+		Email:       u.Email,
+		IsChirpyRed: u.IsChirpyRed,
+		DisplayName: u.DisplayName,
+		Bio:         u.Bio,
+		BioHTML:     linkify(u.Bio),
+	}
 }
 
 // Handler für /api/chirps (POST)
-// Erwartet JSON {"body": "...", "user_id": "..."}.
+// Erwartet JSON {"body": "..."} oder {"template_id": "...", "variables": {...}} und ein gültiges
+// Access-Token; Autor ist der User aus dem Token.
 // Prüft die Länge und ersetzt ggf. "böse" Wörter. Speichert das Chirp in der DB und gibt es als JSON zurück.
 func (cfg *apiConfig) handlerCreateChirp(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPost {
-		http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
-		return
+	type requestBody struct {
+		Body       string            `json:"body"`
+		ID         *uuid.UUID        `json:"id"`          // Optional: vom Client erzeugte UUID v4 für idempotentes Sync
+		TemplateID *uuid.UUID        `json:"template_id"` // Optional: Body aus einer eigenen Vorlage statt "body"
+		Variables  map[string]string `json:"variables"`   // Werte für die {{name}}-Platzhalter der Vorlage
 	}
 
-	type requestBody struct {
-		Body   string    `json:"body"`
-		UserID uuid.UUID `json:"user_id"`
+	var req requestBody
+	if reqErr := decodeJSONBody(w, r, &req); reqErr != nil {
+		respondWithRequestError(w, reqErr)
+		return
 	}
-	type errorResponse struct {
-		Error string `json:"error"`
+	var warnings []string
+	if req.TemplateID != nil {
+		if req.Body != "" {
+			respondWithError(w, http.StatusBadRequest, "Send either body or template_id, not both", nil)
+			return
+		}
+		template, reqErr := cfg.templateForUser(r, *req.TemplateID)
+		if reqErr != nil {
+			respondWithRequestError(w, reqErr)
+			return
+		}
+		// Die Vorlage wird vor der normalen Prüfung gerendert, damit das Längenlimit für das Ergebnis gilt.
+		req.Body, warnings = renderTemplate(template.Body, req.Variables)
 	}
-	type chirpResponse struct {
-		ID        uuid.UUID `json:"id"`
-		Body      string    `json:"body"`
-		UserID    uuid.UUID `json:"user_id"`
-		CreatedAt time.Time `json:"created_at"`
-		UpdatedAt time.Time `json:"updated_at"`
+	if req.Body == "" {
+		respondWithErrorCode(w, http.StatusBadRequest, errCodeMissingField, "body is required", nil)
+		return
+	}
+	if req.ID != nil && (req.ID.Version() != 4 || req.ID.Variant() != uuid.RFC4122) {
+		respondWithError(w, http.StatusBadRequest, "id must be a version 4 UUID", nil)
+		return
 	}
 
-	var req requestBody
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Body == "" || req.UserID == uuid.Nil {
-		http.Error(w, `{"error":"invalid request"}`, http.StatusBadRequest)
+	body, reqErr := validateChirpBody(req.Body, cfg.bidiPolicy)
+	if reqErr != nil {
+		respondWithRequestError(w, reqErr)
 		return
 	}
 
-	if len(req.Body) > 140 {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(errorResponse{Error: "Chirp is too long"})
+	cleanedBody, maskedRanges := cleanProfanity(body, cfg.bannedWords, cfg.stripDiacritics)
+	maskedRangesJSON, err := json.Marshal(maskedRanges)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't encode masked ranges", err)
 		return
 	}
 
-	// Profanity-Filter anwenden
-	badWords := map[string]struct{}{
-		"kerfuffle": {},
-		"sharbert":  {},
-		"fornax":    {},
+	// Chirp in der Datenbank speichern; bei einer Kollision der Short-ID mit neuer ID erneut versuchen
+	id := uuid.New()
+	if req.ID != nil {
+		id = *req.ID
+	}
+	userID := userIDFromContext(r.Context())
+	now := cfg.clock.Now().UTC()
+	var chirp database.Chirp
+	for attempt := 0; attempt < shortIDMaxRetries; attempt++ {
+		var shortID string
+		shortID, err = newShortID()
+		if err != nil {
+			break
+		}
+		chirp, err = cfg.db.CreateChirp(r.Context(), database.CreateChirpParams{
+			ID:           id,
+			CreatedAt:    now,
+			UpdatedAt:    now,
+			Body:         cleanedBody,
+			UserID:       userID,
+			ShortID:      shortID,
+			MaskedRanges: maskedRangesJSON,
+		})
+		if !isShortIDCollision(err) {
+			break
+		}
 	}
-	words := strings.Split(req.Body, " ")
-	for i, word := range words {
-		if _, found := badWords[strings.ToLower(word)]; found {
-			words[i] = "****"
+	if isUniqueViolation(err, "chirps_pkey") {
+		// Die ID gibt es schon: Gehört der Chirp demselben Autor, war es eine Wiederholung
+		// und der vorhandene Chirp wird zurückgegeben. Sonst ist es ein echter Konflikt.
+		existing, getErr := cfg.db.GetChirpByID(r.Context(), id)
+		if getErr != nil {
+			respondWithError(w, http.StatusInternalServerError, "Couldn't retrieve chirp", getErr)
+			return
+		}
+		if existing.UserID != userID {
+			respondWithError(w, http.StatusConflict, "A chirp with this id already exists", nil)
+			return
+		}
+		chirpResp, err := cfg.chirpJSONFor(r, existing)
+		if err != nil {
+			respondWithError(w, http.StatusInternalServerError, "Couldn't retrieve chirp", err)
+			return
 		}
+		respondWithJSON(w, http.StatusOK, chirpResp)
+		return
 	}
-	cleanedBody := strings.Join(words, " ")
-
-	// Chirp in der Datenbank speichern
-	id := uuid.New()
-	now := time.Now().UTC()
-	chirp, err := cfg.db.CreateChirp(r.Context(), database.CreateChirpParams{
-		ID:        id,
-		CreatedAt: now,
-		UpdatedAt: now,
-		Body:      cleanedBody,
-		UserID:    req.UserID,
-	})
 	if err != nil {
-		http.Error(w, `{"error":"could not create chirp"}`, http.StatusInternalServerError)
+		respondWithError(w, http.StatusInternalServerError, "Couldn't create chirp", err)
 		return
 	}
 
 	// Chirp als JSON zurückgeben
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusCreated)
-	json.NewEncoder(w).Encode(chirpResponse{
-		ID:        chirp.ID,
-		Body:      chirp.Body,
-		UserID:    chirp.UserID,
-		CreatedAt: chirp.CreatedAt,
-		UpdatedAt: chirp.UpdatedAt,
-	})
+	chirpResp, err := cfg.chirpJSONFor(r, chirp)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't retrieve chirp", err)
+		return
+	}
+	chirpResp.Warnings = warnings
+	w.Header().Set("Location", chirpResp.URL)
+	respondWithJSON(w, http.StatusCreated, chirpResp)
 }
 
-/*
-Dokumentation:
---------------
-handlerReset:
-  - Setzt den Zugriffszähler (fileserverHits) auf 0 zurück.
-  - Antwort: HTTP 200 OK, Body: "OK"
-  - In einer erweiterten Version kann hier auch das Löschen aller User aus der Datenbank erfolgen,
-    z.B. durch Aufruf von cfg.db.DeleteAllUsers(r.Context()), wenn PLATFORM == "dev".
-
-handlerMetrics:
-  - Gibt eine HTML-Seite mit der aktuellen Anzahl der Zugriffe auf /app/ zurück.
-  - Antwort: HTTP 200 OK, Content-Type: text/html
-  - Die Zahl wird aus cfg.fileserverHits.Load() gelesen.
-*/
+// Permalink eines Chirps, aufgebaut aus BASE_URL, BASE_PATH und der Short-ID
+func (cfg *apiConfig) chirpURL(shortID string) string {
+	return cfg.baseURL + cfg.urlPath("/api/chirps/"+shortID)
+}
@@ -0,0 +1,157 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/nuke87/go_http_server/internal/auth"
+	"github.com/nuke87/go_http_server/pkg/chirpy"
+)
+
+func TestCreateUserThenChirp(t *testing.T) {
+	ts := newTestServer(t)
+	user, token := ts.createUser(t, "Alice@Example.COM")
+
+	// Der zurückgegebene User ist der gespeicherte, nicht ein frisch erzeugtes Objekt
+	stored, err := ts.store.GetUserByEmail(context.Background(), "Alice@example.com")
+	if err != nil {
+		t.Fatalf("user not stored: %v", err)
+	}
+	if stored.ID != user.ID || !stored.CreatedAt.Equal(user.CreatedAt) || user.Email != stored.Email {
+		t.Errorf("response %+v does not match stored user %+v", user, stored)
+	}
+	if !user.CreatedAt.Equal(ts.now) {
+		t.Errorf("created_at = %v, want server time %v", user.CreatedAt, ts.now)
+	}
+
+	chirp := ts.createChirp(t, token, "hello from alice")
+	if chirp.UserID != user.ID || chirp.Body != "hello from alice" {
+		t.Errorf("chirp = %+v, want author %s", chirp, user.ID)
+	}
+	rec := ts.do(t, "GET", "/api/chirps/"+chirp.ID.String(), "", "")
+	expectStatus(t, rec, http.StatusOK)
+	if got := decodeResponse[chirpy.Chirp](t, rec); got.UserID != user.ID {
+		t.Errorf("fetched chirp author = %s, want %s", got.UserID, user.ID)
+	}
+}
+
+func TestCreateUserDuplicateEmail(t *testing.T) {
+	ts := newTestServer(t)
+	ts.createUser(t, "alice@example.com")
+
+	rec := ts.do(t, "POST", "/api/users", "", `{"email":"alice@EXAMPLE.com","password":"other-pass"}`)
+	expectStatus(t, rec, http.StatusConflict)
+
+	users, err := ts.store.GetUsers(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(users) != 1 {
+		t.Errorf("got %d users after duplicate signup, want 1", len(users))
+	}
+}
+
+// Alle 400-Antworten von POST /api/users und POST /api/chirps sind JSON-Fehlerobjekte.
+func TestCreateBadRequestIsJSON(t *testing.T) {
+	ts := newTestServer(t)
+	_, token := ts.createUser(t, "alice@example.com")
+	tests := []struct {
+		name, path, token, body string
+	}{
+		{"user without email", "/api/users", "", `{"password":"hunter22"}`},
+		{"user without password", "/api/users", "", `{"email":"bob@example.com"}`},
+		{"user with invalid email", "/api/users", "", `{"email":"bob","password":"hunter22"}`},
+		{"user with malformed json", "/api/users", "", `{"email":`},
+		{"chirp without body", "/api/chirps", token, `{}`},
+		{"chirp too long", "/api/chirps", token, `{"body":"` + strings.Repeat("x", 141) + `"}`},
+		{"chirp with malformed json", "/api/chirps", token, `{"body":1`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := ts.do(t, "POST", tt.path, tt.token, tt.body)
+			if rec.Code != http.StatusBadRequest {
+				t.Fatalf("status = %d, want 400, body %s", rec.Code, rec.Body)
+			}
+			if ct := rec.Header().Get("Content-Type"); ct != "application/json" {
+				t.Errorf("Content-Type = %q, want application/json", ct)
+			}
+			var got chirpy.ErrorResponse
+			if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil || got.Error == "" {
+				t.Errorf("body %q is not an error object: %v", rec.Body, err)
+			}
+		})
+	}
+}
+
+func TestCreateChirpClientID(t *testing.T) {
+	ts := newTestServer(t)
+	alice, aliceToken := ts.createUser(t, "alice@example.com")
+	_, bobToken := ts.createUser(t, "bob@example.com")
+	id := uuid.New()
+	body := `{"id":"` + id.String() + `","body":"synced later"}`
+
+	// Gleichzeitige Wiederholungen desselben Sync: genau ein Insert, alle sehen denselben Chirp
+	const n = 20
+	codes := make([]int, n)
+	chirps := make([]chirpy.Chirp, n)
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			rec := ts.do(t, "POST", "/api/chirps", aliceToken, body)
+			codes[i] = rec.Code
+			json.Unmarshal(rec.Body.Bytes(), &chirps[i])
+		}()
+	}
+	wg.Wait()
+	created := 0
+	for i, code := range codes {
+		switch code {
+		case http.StatusCreated:
+			created++
+		case http.StatusOK:
+		default:
+			t.Fatalf("request %d: status %d", i, code)
+		}
+		if chirps[i].ID != id || chirps[i].UserID != alice.ID || chirps[i].ShortID != chirps[0].ShortID {
+			t.Errorf("request %d returned %+v", i, chirps[i])
+		}
+	}
+	if created != 1 {
+		t.Errorf("%d requests got 201, want exactly 1", created)
+	}
+	all, err := ts.store.GetChirpsAsc(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != 1 {
+		t.Errorf("%d chirps stored, want 1", len(all))
+	}
+
+	// Dieselbe ID von einem anderen Autor ist ein echter Konflikt
+	expectStatus(t, ts.do(t, "POST", "/api/chirps", bobToken, body), http.StatusConflict)
+
+	v1 := uuid.Must(uuid.NewUUID())
+	expectStatus(t, ts.do(t, "POST", "/api/chirps", aliceToken, `{"id":"`+v1.String()+`","body":"x"}`), http.StatusBadRequest)
+	expectStatus(t, ts.do(t, "POST", "/api/users", "", `{"id":"`+uuid.NewString()+`","email":"carol@example.com","password":"hunter22"}`), http.StatusBadRequest)
+}
+
+func TestCreateUserPasswordLimit(t *testing.T) {
+	ts := newTestServer(t)
+	rec := ts.do(t, "POST", "/api/users", "", `{"email":"alice@example.com","password":"`+strings.Repeat("p", auth.MaxPasswordBytes)+`"}`)
+	expectStatus(t, rec, http.StatusCreated)
+
+	// 73 Bytes, auch wenn es weniger Zeichen sind: bcrypt zählt Bytes
+	tooLong := strings.Repeat("ä", auth.MaxPasswordBytes/2) + "p"
+	rec = ts.do(t, "POST", "/api/users", "", `{"email":"bob@example.com","password":"`+tooLong+`"}`)
+	expectStatus(t, rec, http.StatusBadRequest)
+	if got := decodeResponse[chirpy.ErrorResponse](t, rec); got.Code != errCodeTooLong || got.Field != "password" {
+		t.Errorf("code/field = %q/%q, want %q/password", got.Code, got.Field, errCodeTooLong)
+	}
+}
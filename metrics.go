@@ -1,11 +1,38 @@
 package main
 
 import (
+	"bufio"
+	"errors"
 	"fmt"
+	"net"
 	"net/http"
+	"strings"
 )
 
+// Handler für /admin/metrics
+// Gibt eine HTML-Seite (oder JSON bei Accept: application/json) mit der Anzahl der Zugriffe auf /app/ zurück,
+// getrennt nach Menschen und Bots.
 func (cfg *apiConfig) handlerMetrics(w http.ResponseWriter, r *http.Request) {
+	if strings.Contains(r.Header.Get("Accept"), "application/json") {
+		type metricsResponse struct {
+			FileserverHits        int32 `json:"fileserver_hits"`
+			FileserverBotHits     int32 `json:"fileserver_bot_hits"`
+			ResponseClientAborts  int64 `json:"response_client_aborts"`
+			ResponseMarshalErrors int64 `json:"response_marshal_errors"`
+			UptimeSeconds         int64 `json:"uptime_seconds"`
+			RequestsTotal         int64 `json:"requests_total"`
+		}
+		respondWithJSON(w, http.StatusOK, metricsResponse{
+			FileserverHits:        cfg.fileserverHits.Load(),
+			FileserverBotHits:     cfg.fileserverBotHits.Load(),
+			ResponseClientAborts:  responseClientAborts.Load(),
+			ResponseMarshalErrors: responseMarshalErrors.Load(),
+			UptimeSeconds:         cfg.uptimeSeconds(),
+			RequestsTotal:         cfg.requestMetrics.total(),
+		})
+		return
+	}
+
 	w.Header().Add("Content-Type", "text/html")
 	w.WriteHeader(http.StatusOK)
 	w.Write([]byte(fmt.Sprintf(`
@@ -14,16 +41,76 @@ func (cfg *apiConfig) handlerMetrics(w http.ResponseWriter, r *http.Request) {
 <body>
 	<h1>Welcome, Chirpy Admin</h1>
 	<p>Chirpy has been visited %d times!</p>
+	<p>Bots have visited %d times.</p>
 </body>
 
 </html>
-	`, cfg.fileserverHits.Load())))
+	`, cfg.fileserverHits.Load(), cfg.fileserverBotHits.Load())))
 }
 
+// Middleware: Zählt Zugriffe auf /app/, Bots (laut User-Agent) in einem eigenen Zähler.
+// Mit METRICS_SKIP_NOT_MODIFIED werden 304-Antworten nicht als menschliche Zugriffe gezählt.
 func (cfg *apiConfig) middlewareMetricsInc(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		cfg.fileserverHits.Add(1)
-		next.ServeHTTP(w, r)
+		if cfg.botMatcher.Load().isBot(r.UserAgent()) {
+			cfg.fileserverBotHits.Add(1)
+			next.ServeHTTP(w, r)
+			return
+		}
+		if !cfg.skipNotModified {
+			cfg.fileserverHits.Add(1)
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		sw := &statusWriter{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(sw, r)
+		if sw.status != http.StatusNotModified {
+			cfg.fileserverHits.Add(1)
+		}
 	})
 }
 
+// statusWriter merkt sich den geschriebenen HTTP-Statuscode und die Anzahl der Body-Bytes.
+// Flush und Hijack werden an den echten ResponseWriter durchgereicht, Unwrap erlaubt
+// http.ResponseController den Zugriff auf weitere Methoden.
+type statusWriter struct {
+	http.ResponseWriter
+	status      int
+	bytes       int
+	wroteHeader bool
+}
+
+func (sw *statusWriter) WriteHeader(code int) {
+	if !sw.wroteHeader {
+		sw.status = code
+		sw.wroteHeader = true
+	}
+	sw.ResponseWriter.WriteHeader(code)
+}
+
+func (sw *statusWriter) Write(b []byte) (int, error) {
+	sw.wroteHeader = true
+	n, err := sw.ResponseWriter.Write(b)
+	sw.bytes += n
+	return n, err
+}
+
+func (sw *statusWriter) Flush() {
+	if f, ok := sw.ResponseWriter.(http.Flusher); ok {
+		sw.wroteHeader = true
+		f.Flush()
+	}
+}
+
+func (sw *statusWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	h, ok := sw.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, errors.New("response writer does not support hijacking")
+	}
+	return h.Hijack()
+}
+
+func (sw *statusWriter) Unwrap() http.ResponseWriter {
+	return sw.ResponseWriter
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/nuke87/go_http_server/internal/auth"
+)
+
+type contextKey string
+
+const userIDContextKey contextKey = "userID"
+
+// Middleware: Verlangt ein gültiges Bearer-Access-Token und legt die User-ID im Context ab.
+// Fehlende, abgelaufene oder ungültige Tokens werden mit 401 beantwortet.
+func (cfg *apiConfig) middlewareAuth(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, err := auth.GetBearerToken(r.Header)
+		if err != nil {
+			respondWithError(w, http.StatusUnauthorized, "Couldn't find JWT", err)
+			return
+		}
+		userID, err := auth.ValidateJWT(token, cfg.jwtSecret, cfg.clock.Now())
+		if err != nil {
+			respondWithError(w, http.StatusUnauthorized, "Couldn't validate JWT", err)
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), userIDContextKey, userID)))
+	})
+}
+
+// User-ID des authentifizierten Users; nur hinter middlewareAuth gesetzt.
+func userIDFromContext(ctx context.Context) uuid.UUID {
+	userID, _ := ctx.Value(userIDContextKey).(uuid.UUID)
+	return userID
+}
+
+// User-ID aus einem optionalen Bearer-Token, für öffentliche Routen mit personalisierten
+// Feldern. Fehlende oder ungültige Tokens zählen als anonym.
+func (cfg *apiConfig) viewerID(r *http.Request) uuid.NullUUID {
+	if userID := userIDFromContext(r.Context()); userID != uuid.Nil {
+		return uuid.NullUUID{UUID: userID, Valid: true}
+	}
+	token, err := auth.GetBearerToken(r.Header)
+	if err != nil {
+		return uuid.NullUUID{}
+	}
+	userID, err := auth.ValidateJWT(token, cfg.jwtSecret, cfg.clock.Now())
+	if err != nil {
+		return uuid.NullUUID{}
+	}
+	return uuid.NullUUID{UUID: userID, Valid: true}
+}
@@ -0,0 +1,35 @@
+package main
+
+import (
+	"net/http"
+	"strings"
+)
+
+// Middleware: Normalisiert Pfade unter /api, bevor der Mux sie sieht. Doppelte
+// Schrägstriche werden zusammengefasst und ein einzelner abschließender Schrägstrich
+// entfernt. Es wird bewusst nicht umgeleitet, damit POST-Bodies erhalten bleiben.
+// Pfade unter /app/ bleiben unverändert.
+func middlewareNormalizeAPIPath(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if p := normalizeAPIPath(r.URL.Path); p != r.URL.Path {
+			r2 := r.Clone(r.Context())
+			r2.URL.Path = p
+			r2.URL.RawPath = ""
+			r = r2
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func normalizeAPIPath(p string) string {
+	if !strings.HasPrefix(p, "/api/") {
+		return p
+	}
+	for strings.Contains(p, "//") {
+		p = strings.ReplaceAll(p, "//", "/")
+	}
+	if len(p) > 1 && strings.HasSuffix(p, "/") {
+		p = strings.TrimSuffix(p, "/")
+	}
+	return p
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/nuke87/go_http_server/pkg/chirpy"
+)
+
+func TestNormalizeAPIPath(t *testing.T) {
+	tests := []struct{ in, want string }{
+		{"/api/chirps", "/api/chirps"},
+		{"/api/chirps/", "/api/chirps"},
+		{"/api//chirps", "/api/chirps"},
+		{"/api///chirps//abc//", "/api/chirps/abc"},
+		{"/api/", "/api"},
+		{"/app/", "/app/"},
+		{"/app//assets/logo.png", "/app//assets/logo.png"},
+		{"/admin/metrics/", "/admin/metrics/"},
+	}
+	for _, tt := range tests {
+		if got := normalizeAPIPath(tt.in); got != tt.want {
+			t.Errorf("normalizeAPIPath(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+// Requests mit Body kommen über normalisierte Pfade ohne Redirect beim Handler an.
+func TestNormalizedPathsKeepBodies(t *testing.T) {
+	ts := newTestServer(t)
+	_, token := ts.createUser(t, "alice@example.com")
+
+	for _, path := range []string{"/api/chirps/", "/api//chirps", "/api//chirps//"} {
+		t.Run("POST "+path, func(t *testing.T) {
+			rec := ts.do(t, "POST", path, token, `{"body":"via `+path+`"}`)
+			expectStatus(t, rec, http.StatusCreated)
+			if got := decodeResponse[chirpy.Chirp](t, rec); got.Body != "via "+path {
+				t.Errorf("body = %q", got.Body)
+			}
+		})
+	}
+
+	t.Run("POST /api//users/", func(t *testing.T) {
+		rec := ts.do(t, "POST", "/api//users/", "", `{"email":"bob@example.com","password":"hunter22"}`)
+		expectStatus(t, rec, http.StatusCreated)
+	})
+
+	t.Run("PUT with path parameter", func(t *testing.T) {
+		chirp := ts.createChirp(t, token, "before")
+		rec := ts.do(t, "PUT", "/api//chirps/"+chirp.ID.String()+"/", token, `{"body":"after"}`)
+		expectStatus(t, rec, http.StatusOK)
+		if got := decodeResponse[chirpy.Chirp](t, rec); got.Body != "after" {
+			t.Errorf("body = %q", got.Body)
+		}
+	})
+}
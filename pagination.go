@@ -0,0 +1,80 @@
+package main
+
+import (
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	defaultPageLimit = 20
+	maxPageLimit     = 100
+)
+
+// chirpCursor ist die Position des letzten Chirps einer Seite (Keyset-Pagination).
+type chirpCursor struct {
+	CreatedAt time.Time
+	ID        uuid.UUID
+}
+
+// Kodiert den Cursor opak als base64url("<created_at>|<id>").
+func (c chirpCursor) encode() string {
+	raw := c.CreatedAt.UTC().Format(time.RFC3339Nano) + "|" + c.ID.String()
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func decodeChirpCursor(s string) (chirpCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return chirpCursor{}, errors.New("invalid cursor")
+	}
+	createdAtString, idString, ok := strings.Cut(string(raw), "|")
+	if !ok {
+		return chirpCursor{}, errors.New("invalid cursor")
+	}
+	createdAt, err := time.Parse(time.RFC3339Nano, createdAtString)
+	if err != nil {
+		return chirpCursor{}, errors.New("invalid cursor")
+	}
+	id, err := uuid.Parse(idString)
+	if err != nil {
+		return chirpCursor{}, errors.New("invalid cursor")
+	}
+	return chirpCursor{CreatedAt: createdAt, ID: id}, nil
+}
+
+// Cursor vor der ersten Seite: Jeder Chirp liegt in Sortierrichtung dahinter. So kommen
+// die Keyset-Queries ohne "Cursor IS NULL OR ..." aus, das den Index unbrauchbar macht.
+func firstPageCursor(sort string) chirpCursor {
+	if sort == "desc" {
+		return chirpCursor{CreatedAt: time.Date(9999, 12, 31, 23, 59, 59, 0, time.UTC), ID: uuid.Max}
+	}
+	return chirpCursor{CreatedAt: time.Date(1, 1, 1, 0, 0, 0, 0, time.UTC), ID: uuid.Nil}
+}
+
+// Cursor aus ?cursor=, sonst firstPageCursor.
+func pageCursor(raw, sort string) (chirpCursor, error) {
+	if raw == "" {
+		return firstPageCursor(sort), nil
+	}
+	return decodeChirpCursor(raw)
+}
+
+// Liest ?limit=; Standard defaultPageLimit, Werte über maxPageLimit werden gekappt.
+func parsePageLimit(s string) (int, error) {
+	if s == "" {
+		return defaultPageLimit, nil
+	}
+	limit, err := strconv.Atoi(s)
+	if err != nil || limit < 1 {
+		return 0, errors.New("limit must be a positive integer")
+	}
+	if limit > maxPageLimit {
+		limit = maxPageLimit
+	}
+	return limit, nil
+}
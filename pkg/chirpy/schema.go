@@ -0,0 +1,109 @@
+package chirpy
+
+import (
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Schemas liefert für jeden Antworttyp ein JSON-Schema (Draft 2020-12), nach Namen.
+// Die Schemas werden per Reflection aus den Typen dieses Pakets erzeugt und verbieten
+// unbekannte Felder, damit Änderungen am Format auffallen.
+func Schemas() map[string]map[string]any {
+	chirp := schemaOf(reflect.TypeOf(Chirp{}))
+	return map[string]map[string]any{
+		"user":           withMeta("user", schemaOf(reflect.TypeOf(User{}))),
+		"login":          withMeta("login", schemaOf(reflect.TypeOf(LoginResponse{}))),
+		"chirp":          withMeta("chirp", chirp),
+		"chirp_list":     withMeta("chirp_list", map[string]any{"type": "array", "items": chirp}),
+		"chirp_page":     withMeta("chirp_page", schemaOf(reflect.TypeOf(ChirpPage{}))),
+		"chirp_template": withMeta("chirp_template", schemaOf(reflect.TypeOf(ChirpTemplate{}))),
+		"error":          withMeta("error", schemaOf(reflect.TypeOf(ErrorResponse{}))),
+	}
+}
+
+func withMeta(name string, schema map[string]any) map[string]any {
+	out := map[string]any{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"$id":     name + ".json",
+	}
+	for k, v := range schema {
+		out[k] = v
+	}
+	return out
+}
+
+var (
+	timeType = reflect.TypeOf(time.Time{})
+	uuidType = reflect.TypeOf(uuid.UUID{})
+)
+
+func schemaOf(t reflect.Type) map[string]any {
+	switch {
+	case t == timeType:
+		return map[string]any{"type": "string", "format": "date-time"}
+	case t == uuidType:
+		return map[string]any{"type": "string", "format": "uuid"}
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		inner := schemaOf(t.Elem())
+		inner["type"] = []any{inner["type"], "null"}
+		return inner
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	case reflect.Slice, reflect.Array:
+		return map[string]any{"type": "array", "items": schemaOf(t.Elem())}
+	case reflect.Map:
+		return map[string]any{"type": "object", "additionalProperties": schemaOf(t.Elem())}
+	case reflect.Struct:
+		properties := map[string]any{}
+		required := []string{}
+		addStructFields(t, properties, &required)
+		return map[string]any{
+			"type":                 "object",
+			"properties":           properties,
+			"required":             required,
+			"additionalProperties": false,
+		}
+	default:
+		return map[string]any{}
+	}
+}
+
+// Übernimmt die JSON-Felder eines Structs; eingebettete Structs werden flach übernommen
+// wie bei encoding/json. Felder ohne omitempty sind Pflichtfelder.
+func addStructFields(t reflect.Type, properties map[string]any, required *[]string) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() && !f.Anonymous {
+			continue
+		}
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		if f.Anonymous && name == "" && f.Type.Kind() == reflect.Struct {
+			addStructFields(f.Type, properties, required)
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		properties[name] = schemaOf(f.Type)
+		if !strings.Contains(opts, "omitempty") {
+			*required = append(*required, name)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+// Package chirpy enthält die JSON-Typen der Chirpy-API, damit Server und Clients
+// dieselben Formen verwenden.
+package chirpy
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// User ist die JSON-Darstellung eines Users.
+type User struct {
+	ID          uuid.UUID `json:"id"`                 // Eindeutige User-ID (UUID), wird als "id" im JSON ausgegeben
+	CreatedAt   time.Time `json:"created_at"`         // Erstellungszeitpunkt, wird als "created_at" im JSON ausgegeben
+	UpdatedAt   time.Time `json:"updated_at"`         // Zeitpunkt der letzten Änderung, wird als "updated_at" im JSON ausgegeben
+	Email       string    `json:"email"`              // E-Mail-Adresse des Users, wird als "email" im JSON ausgegeben
+	IsChirpyRed bool      `json:"is_chirpy_red"`      // Hat der User Chirpy Red gebucht?
+	DisplayName string    `json:"display_name"`       // Anzeigename, bereinigt wie ein Chirp; leer wenn nicht gesetzt
+	Bio         string    `json:"bio"`                // Kurzbeschreibung, bereinigt wie ein Chirp; leer wenn nicht gesetzt
+	BioHTML     string    `json:"bio_html,omitempty"` // Bio als HTML mit verlinkten URLs, fehlt bei leerer Bio
+}
+
+// LoginResponse ist die Antwort auf POST /api/login.
+type LoginResponse struct {
+	User
+	Token        string `json:"token"`         // JWT-Access-Token für den Authorization-Header
+	RefreshToken string `json:"refresh_token"` // Langlebiges Token für POST /api/refresh
+}
+
+// Chirp ist die JSON-Darstellung eines Chirps.
+type Chirp struct {
+	ID        uuid.UUID `json:"id"`
+	Body      string    `json:"body"`
+	UserID    uuid.UUID `json:"user_id"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	ShortID   string    `json:"short_id"`
+	URL       string    `json:"url"`
+	Edited    bool      `json:"edited"` // Body wurde nach dem Anlegen geändert (updated_at != created_at)
+	LikeCount int64     `json:"like_count"`
+	LikedByMe *bool     `json:"liked_by_me,omitempty"` // Nur wenn der Request ein gültiges Access-Token trägt
+	Warnings  []string  `json:"warnings,omitempty"`    // Nur beim Anlegen aus einer Vorlage, z.B. unbekannte Variablen
+	// Nur für den angemeldeten Autor und wenn das Bearbeiten zeitlich begrenzt ist
+	EditableUntil *time.Time `json:"editable_until,omitempty"`
+	// Nur mit ?include_entities=true und wenn der Profanity-Filter etwas ersetzt hat
+	MaskedRanges []MaskedRange `json:"masked_ranges,omitempty"`
+}
+
+// MaskedRange beschreibt ein vom Profanity-Filter ersetztes Wort. Start und End sind
+// Rune-Offsets (End exklusiv) im gespeicherten, bereinigten Body.
+type MaskedRange struct {
+	Original    string `json:"original"`
+	Replacement string `json:"replacement"`
+	Start       int    `json:"start"`
+	End         int    `json:"end"`
+}
+
+// ChirpTemplate ist eine gespeicherte Textvorlage eines Users mit {{name}}-Platzhaltern.
+type ChirpTemplate struct {
+	ID        uuid.UUID `json:"id"`
+	Name      string    `json:"name"`
+	Body      string    `json:"body"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// ChirpPage ist eine Seite der paginierten Chirp-Liste.
+type ChirpPage struct {
+	Chirps     []Chirp `json:"chirps"`
+	NextCursor string  `json:"next_cursor,omitempty"` // Fehlt auf der letzten Seite
+}
+
+// ErrorResponse ist der Body aller Fehlerantworten.
+type ErrorResponse struct {
+	Error string `json:"error"`
+	Code  string `json:"code,omitempty"`  // Maschinenlesbarer Fehlercode, z.B. "empty_body"
+	Field string `json:"field,omitempty"` // Betroffenes Request-Feld bei Validierungsfehlern, z.B. "body"
+}
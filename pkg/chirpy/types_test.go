@@ -0,0 +1,109 @@
+package chirpy
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	testTime    = time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	testUserID  = uuid.MustParse("11111111-1111-4111-8111-111111111111")
+	testChirpID = uuid.MustParse("22222222-2222-4222-8222-222222222222")
+)
+
+// Kodiert v, dekodiert es wieder und vergleicht; prüft außerdem die JSON-Feldnamen.
+func roundTrip[T any](t *testing.T, v T, wantKeys ...string) {
+	t.Helper()
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	var got T
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("unmarshal %s: %v", data, err)
+	}
+	if !reflect.DeepEqual(got, v) {
+		t.Errorf("round trip changed the value\n got %+v\nwant %+v", got, v)
+	}
+	var keys map[string]json.RawMessage
+	if err := json.Unmarshal(data, &keys); err != nil {
+		t.Fatalf("%s is not an object: %v", data, err)
+	}
+	if len(keys) != len(wantKeys) {
+		t.Errorf("got keys %v, want %v", reflect.ValueOf(keys).MapKeys(), wantKeys)
+	}
+	for _, key := range wantKeys {
+		if _, ok := keys[key]; !ok {
+			t.Errorf("key %q missing in %s", key, data)
+		}
+	}
+}
+
+func TestUserRoundTrip(t *testing.T) {
+	user := User{
+		ID: testUserID, CreatedAt: testTime, UpdatedAt: testTime.Add(time.Hour),
+		Email: "alice@example.com", IsChirpyRed: true,
+		DisplayName: "Alice", Bio: "hi https://example.com", BioHTML: `hi <a href="https://example.com">https://example.com</a>`,
+	}
+	roundTrip(t, user, "id", "created_at", "updated_at", "email", "is_chirpy_red", "display_name", "bio", "bio_html")
+	// bio_html fehlt bei leerer Bio, display_name und bio bleiben als ""
+	roundTrip(t, User{ID: testUserID, CreatedAt: testTime, UpdatedAt: testTime, Email: "bob@example.com"},
+		"id", "created_at", "updated_at", "email", "is_chirpy_red", "display_name", "bio")
+}
+
+func TestLoginResponseRoundTrip(t *testing.T) {
+	login := LoginResponse{
+		User:         User{ID: testUserID, CreatedAt: testTime, UpdatedAt: testTime, Email: "alice@example.com"},
+		Token:        "access",
+		RefreshToken: "refresh",
+	}
+	// Die User-Felder liegen flach neben den Tokens
+	roundTrip(t, login, "id", "created_at", "updated_at", "email", "is_chirpy_red", "display_name", "bio", "token", "refresh_token")
+}
+
+func TestChirpRoundTrip(t *testing.T) {
+	likedByMe := true
+	editableUntil := testTime.Add(15 * time.Minute)
+	chirp := Chirp{
+		ID: testChirpID, Body: "what a ****", UserID: testUserID,
+		CreatedAt: testTime, UpdatedAt: testTime.Add(time.Minute),
+		ShortID: "abc123", URL: "http://chirpy.test/c/abc123", Edited: true, LikeCount: 3,
+		LikedByMe:     &likedByMe,
+		Warnings:      []string{"unknown variable {{name}}"},
+		EditableUntil: &editableUntil,
+		MaskedRanges:  []MaskedRange{{Original: "kerfuffle", Replacement: "****", Start: 7, End: 11}},
+	}
+	roundTrip(t, chirp, "id", "body", "user_id", "created_at", "updated_at", "short_id", "url", "edited",
+		"like_count", "liked_by_me", "warnings", "editable_until", "masked_ranges")
+	// Optionale Felder fehlen ganz, statt als null zu erscheinen
+	roundTrip(t, Chirp{ID: testChirpID, Body: "hi", UserID: testUserID, CreatedAt: testTime, UpdatedAt: testTime, ShortID: "abc123"},
+		"id", "body", "user_id", "created_at", "updated_at", "short_id", "url", "edited", "like_count")
+}
+
+func TestMaskedRangeRoundTrip(t *testing.T) {
+	roundTrip(t, MaskedRange{Original: "fornax", Replacement: "****", Start: 0, End: 4},
+		"original", "replacement", "start", "end")
+}
+
+func TestChirpTemplateRoundTrip(t *testing.T) {
+	roundTrip(t, ChirpTemplate{ID: testChirpID, Name: "greeting", Body: "hi {{name}}", CreatedAt: testTime, UpdatedAt: testTime},
+		"id", "name", "body", "created_at", "updated_at")
+}
+
+func TestChirpPageRoundTrip(t *testing.T) {
+	page := ChirpPage{
+		Chirps:     []Chirp{{ID: testChirpID, Body: "hi", UserID: testUserID, CreatedAt: testTime, UpdatedAt: testTime, ShortID: "abc123"}},
+		NextCursor: "cursor",
+	}
+	roundTrip(t, page, "chirps", "next_cursor")
+	roundTrip(t, ChirpPage{Chirps: []Chirp{}}, "chirps")
+}
+
+func TestErrorResponseRoundTrip(t *testing.T) {
+	roundTrip(t, ErrorResponse{Error: "body is required", Code: "missing_field", Field: "body"}, "error", "code", "field")
+	roundTrip(t, ErrorResponse{Error: "Couldn't retrieve chirps"}, "error")
+}
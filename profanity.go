@@ -0,0 +1,100 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/nuke87/go_http_server/pkg/chirpy"
+)
+
+// Ersatztext für ein gefiltertes Wort
+const profanityMask = "****"
+
+// Gefilterte Wörter, wenn weder BANNED_WORDS noch BANNED_WORDS_FILE gesetzt ist
+var defaultBannedWords = []string{"kerfuffle", "sharbert", "fornax"}
+
+// Lädt die Wortliste: BANNED_WORDS (kommagetrennt) oder BANNED_WORDS_FILE (JSON-Array
+// von Strings). Beides gleichzeitig ist ein Fehler.
+func bannedWordsFromEnv() ([]string, error) {
+	list, file := os.Getenv("BANNED_WORDS"), os.Getenv("BANNED_WORDS_FILE")
+	switch {
+	case list != "" && file != "":
+		return nil, fmt.Errorf("set either BANNED_WORDS or BANNED_WORDS_FILE, not both")
+	case list != "":
+		var words []string
+		for _, word := range strings.Split(list, ",") {
+			if word = strings.TrimSpace(word); word != "" {
+				words = append(words, word)
+			}
+		}
+		return words, nil
+	case file != "":
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		var words []string
+		if err := json.Unmarshal(data, &words); err != nil {
+			return nil, fmt.Errorf("%s must contain a JSON array of strings: %w", file, err)
+		}
+		return words, nil
+	}
+	return defaultBannedWords, nil
+}
+
+// Ersetzt verbotene Wörter durch profanityMask. Verglichen wird Token für Token, wobei
+// ein Token alles zwischen zwei Whitespace-Folgen ist; der Whitespace selbst bleibt
+// unverändert erhalten. Satzzeichen gehören zum Token: "kerfuffle!" oder "(fornax)"
+// werden bewusst nicht ersetzt, wie in der Chirpy-Spezifikation. Wortliste und Tokens
+// werden gleich normalisiert (siehe foldForMatch).
+//
+// Neben dem bereinigten Body werden die ersetzten Stellen geliefert, mit Rune-Offsets im
+// bereinigten Body.
+func cleanProfanity(body string, badWords []string, stripDiacritics bool) (string, []chirpy.MaskedRange) {
+	banned := make(map[string]struct{}, len(badWords))
+	for _, bad := range badWords {
+		banned[foldForMatch(bad, stripDiacritics)] = struct{}{}
+	}
+
+	var b strings.Builder
+	b.Grow(len(body))
+	var masked []chirpy.MaskedRange
+	offset := 0 // Position in Runes im bereinigten Body
+	for body != "" {
+		// Whitespace übernehmen
+		end := strings.IndexFunc(body, func(r rune) bool { return !unicode.IsSpace(r) })
+		if end < 0 {
+			end = len(body)
+		}
+		b.WriteString(body[:end])
+		offset += utf8.RuneCountInString(body[:end])
+		body = body[end:]
+		if body == "" {
+			break
+		}
+
+		// Token bis zum nächsten Whitespace
+		end = strings.IndexFunc(body, unicode.IsSpace)
+		if end < 0 {
+			end = len(body)
+		}
+		token := body[:end]
+		body = body[end:]
+		if _, found := banned[foldForMatch(token, stripDiacritics)]; found {
+			masked = append(masked, chirpy.MaskedRange{
+				Original:    token,
+				Replacement: profanityMask,
+				Start:       offset,
+				End:         offset + utf8.RuneCountInString(profanityMask),
+			})
+			token = profanityMask
+		}
+		b.WriteString(token)
+		offset += utf8.RuneCountInString(token)
+	}
+	return b.String(), masked
+}
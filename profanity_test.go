@@ -0,0 +1,100 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/nuke87/go_http_server/pkg/chirpy"
+)
+
+func TestCleanProfanity(t *testing.T) {
+	tests := []struct {
+		name  string
+		body  string
+		words []string
+		want  string
+	}{
+		{"no bad words", "hello world", defaultBannedWords, "hello world"},
+		{"single word", "what a kerfuffle today", defaultBannedWords, "what a **** today"},
+		{"every word from the list", "kerfuffle sharbert fornax", defaultBannedWords, "**** **** ****"},
+		{"case-insensitive", "KERFUFFLE Sharbert", defaultBannedWords, "**** ****"},
+		// Satzzeichen gehören zum Token und verhindern das Ersetzen (Chirpy-Spezifikation)
+		{"trailing punctuation", "kerfuffle! fornax.", defaultBannedWords, "kerfuffle! fornax."},
+		{"parentheses", "(sharbert)", defaultBannedWords, "(sharbert)"},
+		{"part of a longer word", "kerfuffled sharberts", defaultBannedWords, "kerfuffled sharberts"},
+		{"keeps repeated spaces", "a  kerfuffle   b", defaultBannedWords, "a  ****   b"},
+		{"keeps tabs and newlines", "\tkerfuffle\n\nfornax ", defaultBannedWords, "\t****\n\n**** "},
+		{"custom list", "darn kerfuffle", []string{"darn"}, "**** kerfuffle"},
+		{"empty list", "kerfuffle", nil, "kerfuffle"},
+		{"empty body", "", defaultBannedWords, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, _ := cleanProfanity(tt.body, tt.words, true)
+			if got != tt.want {
+				t.Errorf("cleanProfanity(%q) = %q, want %q", tt.body, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCleanProfanityMaskedRanges(t *testing.T) {
+	// Offsets zählen Runes im bereinigten Body, nicht Bytes
+	got, masked := cleanProfanity("ä kerfuffle und Fornax", defaultBannedWords, true)
+	if got != "ä **** und ****" {
+		t.Fatalf("body = %q", got)
+	}
+	want := []chirpy.MaskedRange{
+		{Original: "kerfuffle", Replacement: profanityMask, Start: 2, End: 6},
+		{Original: "Fornax", Replacement: profanityMask, Start: 11, End: 15},
+	}
+	if !reflect.DeepEqual(masked, want) {
+		t.Errorf("masked = %+v, want %+v", masked, want)
+	}
+	if _, masked := cleanProfanity("clean", defaultBannedWords, true); masked != nil {
+		t.Errorf("masked = %+v for a clean body, want nil", masked)
+	}
+}
+
+func TestBannedWordsFromEnv(t *testing.T) {
+	dir := t.TempDir()
+	validFile := filepath.Join(dir, "words.json")
+	if err := os.WriteFile(validFile, []byte(`["darn", "heck"]`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	invalidFile := filepath.Join(dir, "invalid.json")
+	if err := os.WriteFile(invalidFile, []byte(`{"words": ["darn"]}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		list    string
+		file    string
+		want    []string
+		wantErr bool
+	}{
+		{"default", "", "", defaultBannedWords, false},
+		{"list", " darn, heck ,, ", "", []string{"darn", "heck"}, false},
+		{"file", "", validFile, []string{"darn", "heck"}, false},
+		{"file is not an array", "", invalidFile, nil, true},
+		{"missing file", "", filepath.Join(dir, "missing.json"), nil, true},
+		{"both", "darn", validFile, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("BANNED_WORDS", tt.list)
+			t.Setenv("BANNED_WORDS_FILE", tt.file)
+			got, err := bannedWordsFromEnv()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("words = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
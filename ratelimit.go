@@ -0,0 +1,161 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const errCodeRateLimited = "rate_limited"
+
+// Namen der Limiter für routeOptions.RateLimit
+const (
+	rateLimitLogin = "login" // LOGIN_RATE, Standard 5/min
+	rateLimitChirp = "chirp" // CHIRP_RATE, Standard 30/min
+)
+
+// rateLimit erlaubt Requests Anfragen pro Per, Bursts bis Requests eingeschlossen.
+type rateLimit struct {
+	Requests int
+	Per      time.Duration
+}
+
+// Liest ein Limit der Form "<n>/<einheit>" mit s, min oder h, z.B. "5/min".
+// "off" oder "0" schaltet das Limit ab (Nullwert).
+func parseRateLimit(raw string) (rateLimit, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "off" || raw == "0" {
+		return rateLimit{}, nil
+	}
+	countString, unit, ok := strings.Cut(raw, "/")
+	count, err := strconv.Atoi(strings.TrimSpace(countString))
+	if !ok || err != nil || count <= 0 {
+		return rateLimit{}, fmt.Errorf("rate limit must look like 5/min, got %q", raw)
+	}
+	var per time.Duration
+	switch strings.TrimSpace(unit) {
+	case "s", "sec", "second":
+		per = time.Second
+	case "m", "min", "minute":
+		per = time.Minute
+	case "h", "hour":
+		per = time.Hour
+	default:
+		return rateLimit{}, fmt.Errorf("rate limit unit must be s, min or h, got %q", raw)
+	}
+	return rateLimit{Requests: count, Per: per}, nil
+}
+
+// rateLimiter ist ein Token-Bucket pro Client. Volle Buckets werden höchstens einmal pro
+// Per aufgeräumt, damit die Map nicht mit jedem neuen Client wächst.
+type rateLimiter struct {
+	limit     rateLimit
+	mu        sync.Mutex
+	buckets   map[string]*tokenBucket
+	lastSweep time.Time
+}
+
+type tokenBucket struct {
+	tokens  float64
+	updated time.Time
+}
+
+func newRateLimiter(limit rateLimit) *rateLimiter {
+	return &rateLimiter{limit: limit, buckets: map[string]*tokenBucket{}}
+}
+
+// Nimmt ein Token für key. Ist keins übrig, liefert allow false und die Wartezeit bis zum nächsten.
+func (l *rateLimiter) allow(key string, now time.Time) (bool, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if now.Sub(l.lastSweep) >= l.limit.Per {
+		l.sweep(now)
+	}
+
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &tokenBucket{tokens: float64(l.limit.Requests), updated: now}
+		l.buckets[key] = b
+	}
+	l.refill(b, now)
+	if b.tokens >= 1 {
+		b.tokens--
+		return true, 0
+	}
+	return false, time.Duration((1 - b.tokens) / l.ratePerSecond() * float64(time.Second))
+}
+
+func (l *rateLimiter) ratePerSecond() float64 {
+	return float64(l.limit.Requests) / l.limit.Per.Seconds()
+}
+
+func (l *rateLimiter) refill(b *tokenBucket, now time.Time) {
+	elapsed := now.Sub(b.updated).Seconds()
+	if elapsed <= 0 {
+		return // Uhr zurückgestellt (time-travel) oder gleicher Zeitpunkt
+	}
+	b.tokens = math.Min(float64(l.limit.Requests), b.tokens+elapsed*l.ratePerSecond())
+	b.updated = now
+}
+
+// Entfernt Buckets, die wieder voll wären; sie verhalten sich wie ein neuer Client.
+func (l *rateLimiter) sweep(now time.Time) {
+	for key, b := range l.buckets {
+		l.refill(b, now)
+		if b.tokens >= float64(l.limit.Requests) {
+			delete(l.buckets, key)
+		}
+	}
+	l.lastSweep = now
+}
+
+// Liest LOGIN_RATE und CHIRP_RATE. Abgeschaltete Limiter stehen mit nil in der Map,
+// damit registerRoutes unbekannte Namen von abgeschalteten unterscheiden kann.
+func rateLimitersFromEnv() (map[string]*rateLimiter, error) {
+	defaults := []struct{ name, env, fallback string }{
+		{rateLimitLogin, "LOGIN_RATE", "5/min"},
+		{rateLimitChirp, "CHIRP_RATE", "30/min"},
+	}
+	limiters := map[string]*rateLimiter{}
+	for _, d := range defaults {
+		raw := os.Getenv(d.env)
+		if raw == "" {
+			raw = d.fallback
+		}
+		limit, err := parseRateLimit(raw)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", d.env, err)
+		}
+		limiters[d.name] = nil
+		if limit.Requests > 0 {
+			limiters[d.name] = newRateLimiter(limit)
+		}
+	}
+	return limiters, nil
+}
+
+// Middleware: Begrenzt Requests pro User (hinter middlewareAuth) bzw. pro Client-IP.
+// Über dem Limit gibt es 429 mit Retry-After in Sekunden.
+func (cfg *apiConfig) middlewareRateLimit(limiter *rateLimiter, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := "ip:" + clientIP(r)
+		if userID := userIDFromContext(r.Context()); userID != uuid.Nil {
+			key = "user:" + userID.String()
+		}
+		ok, retryAfter := limiter.allow(key, cfg.clock.Now())
+		if !ok {
+			seconds := max(1, int(math.Ceil(retryAfter.Seconds())))
+			w.Header().Set("Retry-After", strconv.Itoa(seconds))
+			respondWithErrorCode(w, http.StatusTooManyRequests, errCodeRateLimited, "Too many requests, try again later", nil)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
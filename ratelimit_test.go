@@ -0,0 +1,141 @@
+package main
+
+import (
+	"net/http"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestParseRateLimit(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    rateLimit
+		wantErr bool
+	}{
+		{"5/min", rateLimit{5, time.Minute}, false},
+		{" 30 / m ", rateLimit{30, time.Minute}, false},
+		{"10/s", rateLimit{10, time.Second}, false},
+		{"100/hour", rateLimit{100, time.Hour}, false},
+		{"off", rateLimit{}, false},
+		{"0", rateLimit{}, false},
+		{"5", rateLimit{}, true},
+		{"-1/min", rateLimit{}, true},
+		{"5/day", rateLimit{}, true},
+		{"many/min", rateLimit{}, true},
+	}
+	for _, tt := range tests {
+		got, err := parseRateLimit(tt.in)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("parseRateLimit(%q) = %+v, %v; want %+v, err %v", tt.in, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestRateLimiterRefillAndSweep(t *testing.T) {
+	l := newRateLimiter(rateLimit{Requests: 2, Per: time.Minute})
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	for i := 0; i < 2; i++ {
+		if ok, _ := l.allow("a", now); !ok {
+			t.Fatalf("request %d rejected within burst", i+1)
+		}
+	}
+	ok, retryAfter := l.allow("a", now)
+	if ok || retryAfter != 30*time.Second {
+		t.Fatalf("third request: ok=%v retryAfter=%v, want rejected with 30s", ok, retryAfter)
+	}
+	if ok, _ := l.allow("b", now); !ok {
+		t.Error("other key shares the bucket")
+	}
+	if ok, _ := l.allow("a", now.Add(30*time.Second)); !ok {
+		t.Error("no token after 30s refill")
+	}
+
+	// Nach einer vollen Periode sind alle Buckets wieder voll und werden entfernt
+	l.allow("c", now.Add(2*time.Minute))
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if len(l.buckets) != 1 || l.buckets["c"] == nil {
+		t.Errorf("buckets after sweep = %v, want only c", l.buckets)
+	}
+}
+
+func TestRateLimiterConcurrentAllow(t *testing.T) {
+	l := newRateLimiter(rateLimit{Requests: 30, Per: time.Minute})
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	var allowed atomic.Int64
+	var wg sync.WaitGroup
+	for i := 0; i < 200; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if ok, _ := l.allow("ip:192.0.2.1", now); ok {
+				allowed.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+	if got := allowed.Load(); got != 30 {
+		t.Errorf("allowed %d of 200 parallel requests, want exactly 30", got)
+	}
+}
+
+// Parallele Requests über den echten Handler: pro Client-IP bzw. pro User genau das
+// Limit, der Rest bekommt 429 mit Retry-After.
+func TestRateLimitMiddlewareParallel(t *testing.T) {
+	ts := newTestServer(t, func(cfg *apiConfig) {
+		cfg.rateLimiters = map[string]*rateLimiter{
+			rateLimitLogin: newRateLimiter(rateLimit{Requests: 5, Per: time.Minute}),
+			rateLimitChirp: newRateLimiter(rateLimit{Requests: 3, Per: time.Minute}),
+		}
+	})
+	_, aliceToken := ts.createUser(t, "alice@example.com")
+	_, bobToken := ts.createUser(t, "bob@example.com")
+
+	parallel := func(n int, send func() int) map[int]int {
+		var mu sync.Mutex
+		statuses := map[int]int{}
+		var wg sync.WaitGroup
+		for i := 0; i < n; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				code := send()
+				mu.Lock()
+				statuses[code]++
+				mu.Unlock()
+			}()
+		}
+		wg.Wait()
+		return statuses
+	}
+
+	t.Run("login per ip", func(t *testing.T) {
+		statuses := parallel(20, func() int {
+			rec := ts.do(t, "POST", "/api/login", "", `{"email":"nobody@example.com","password":"wrong"}`)
+			if rec.Code == http.StatusTooManyRequests {
+				if s, err := strconv.Atoi(rec.Header().Get("Retry-After")); err != nil || s < 1 {
+					t.Errorf("Retry-After = %q", rec.Header().Get("Retry-After"))
+				}
+			}
+			return rec.Code
+		})
+		if statuses[http.StatusUnauthorized] != 5 || statuses[http.StatusTooManyRequests] != 15 {
+			t.Errorf("statuses = %v, want 5×401 and 15×429", statuses)
+		}
+	})
+
+	t.Run("chirps per user", func(t *testing.T) {
+		for _, token := range []string{aliceToken, bobToken} {
+			statuses := parallel(10, func() int {
+				return ts.do(t, "POST", "/api/chirps", token, `{"body":"hi"}`).Code
+			})
+			if statuses[http.StatusCreated] != 3 || statuses[http.StatusTooManyRequests] != 7 {
+				t.Errorf("statuses = %v, want 3×201 and 7×429 per user", statuses)
+			}
+		}
+	})
+}
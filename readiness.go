@@ -1,9 +1,41 @@
 package main
 
-import "net/http"
+import (
+	"context"
+	"net/http"
+	"time"
+)
 
+// Zeitlimit für den Datenbank-Ping in /api/readyz
+const readinessTimeout = 2 * time.Second
+
+// Pinger prüft, ob eine Abhängigkeit erreichbar ist (z.B. *sql.DB).
+type Pinger interface {
+	PingContext(ctx context.Context) error
+}
+
+// Handler für /api/healthz
+// Liveness-Probe: antwortet immer mit 200, ohne Abhängigkeiten zu prüfen.
 func handlerReadiness(w http.ResponseWriter, r *http.Request) {
 	w.Header().Add("Content-Type", "text/plain; charset=utf-8")
 	w.WriteHeader(http.StatusOK)
 	w.Write([]byte(http.StatusText(http.StatusOK)))
 }
+
+// Handler für /api/readyz (GET)
+// Readiness-Probe: pingt die Datenbank und antwortet mit 503, wenn sie nicht erreichbar ist.
+func (cfg *apiConfig) handlerReadyz(w http.ResponseWriter, r *http.Request) {
+	type response struct {
+		Status string `json:"status"`
+		DB     string `json:"db"`
+		Error  string `json:"error,omitempty"`
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), readinessTimeout)
+	defer cancel()
+	if err := cfg.dbPinger.PingContext(ctx); err != nil {
+		respondWithJSON(w, http.StatusServiceUnavailable, response{Status: "degraded", DB: "down", Error: err.Error()})
+		return
+	}
+	respondWithJSON(w, http.StatusOK, response{Status: "ok", DB: "up"})
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"database/sql"
+	"net/http"
+	"testing"
+)
+
+type readyzResponse struct {
+	Status string `json:"status"`
+	DB     string `json:"db"`
+	Error  string `json:"error"`
+}
+
+func TestReadyz(t *testing.T) {
+	ts := newTestServer(t)
+	rec := ts.do(t, "GET", "/api/readyz", "", "")
+	expectStatus(t, rec, http.StatusOK)
+	if got := decodeResponse[readyzResponse](t, rec); got != (readyzResponse{Status: "ok", DB: "up"}) {
+		t.Errorf("response = %+v", got)
+	}
+}
+
+func TestReadyzClosedDB(t *testing.T) {
+	// sql.Open verbindet noch nicht; nach Close schlägt jeder Ping sofort fehl
+	db, err := sql.Open("postgres", "postgres://chirpy@127.0.0.1:1/chirpy?sslmode=disable")
+	if err != nil {
+		t.Fatal(err)
+	}
+	db.Close()
+	ts := newTestServer(t, func(cfg *apiConfig) { cfg.dbPinger = db })
+
+	rec := ts.do(t, "GET", "/api/readyz", "", "")
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("status = %d, want 503, body %s", rec.Code, rec.Body)
+	}
+	got := decodeResponse[readyzResponse](t, rec)
+	if got.Status != "degraded" || got.DB != "down" || got.Error == "" {
+		t.Errorf("response = %+v", got)
+	}
+
+	// Die Liveness-Probe hängt nicht an der Datenbank
+	if rec := ts.do(t, "GET", "/api/healthz", "", ""); rec.Code != http.StatusOK {
+		t.Errorf("healthz status = %d, want 200", rec.Code)
+	}
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"log"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"github.com/joho/godotenv"
+)
+
+// Lädt bei SIGHUP die .env-Datei neu und übernimmt die neu einlesbaren Einstellungen.
+// Die Werte werden aus processEnv (Umgebung beim Start, vor godotenv.Load) und der frisch
+// gelesenen Datei zusammengesetzt, ohne os.Setenv: Die echte Umgebung gewinnt wie beim Start,
+// und ein aus der .env gelöschter Schlüssel gilt danach wieder als nicht gesetzt.
+func (cfg *apiConfig) watchReload(processEnv map[string]string) {
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, syscall.SIGHUP)
+	for range sigs {
+		fileEnv, err := godotenv.Read()
+		if err != nil {
+			log.Printf("Reload: couldn't read .env, keeping current configuration: %s", err)
+			continue
+		}
+		cfg.botMatcher.Store(botMatcherFromEnv(reloadLookup(processEnv, fileEnv)))
+		log.Println("Reload: configuration reloaded")
+	}
+}
+
+// Sucht zuerst in der Prozess-Umgebung, dann in der .env-Datei.
+func reloadLookup(processEnv, fileEnv map[string]string) func(string) (string, bool) {
+	return func(key string) (string, bool) {
+		if v, ok := processEnv[key]; ok {
+			return v, true
+		}
+		v, ok := fileEnv[key]
+		return v, ok
+	}
+}
+
+// Kopie von os.Environ als Map.
+func environSnapshot() map[string]string {
+	env := map[string]string{}
+	for _, kv := range os.Environ() {
+		if key, value, ok := strings.Cut(kv, "="); ok {
+			env[key] = value
+		}
+	}
+	return env
+}
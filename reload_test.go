@@ -0,0 +1,30 @@
+package main
+
+import "testing"
+
+func TestReloadBotMatcher(t *testing.T) {
+	tests := []struct {
+		name       string
+		processEnv map[string]string
+		fileEnv    map[string]string
+		bot        string // User-Agent, der als Bot gelten muss
+		human      string // User-Agent, der nicht als Bot gelten darf
+	}{
+		{"file value", nil, map[string]string{"BOT_USER_AGENTS": "scraper"}, "MyScraper/1.0", "curl/8.0"},
+		{"process env wins", map[string]string{"BOT_USER_AGENTS": "curl"}, map[string]string{"BOT_USER_AGENTS": "scraper"}, "curl/8.0", "MyScraper/1.0"},
+		// Aus der .env gelöscht: wieder die Standardliste, nicht der alte Wert
+		{"key removed from file", nil, map[string]string{}, "curl/8.0", "MyScraper/1.0"},
+		{"empty value disables", nil, map[string]string{"BOT_USER_AGENTS": ""}, "", "curl/8.0"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := botMatcherFromEnv(reloadLookup(tt.processEnv, tt.fileEnv))
+			if tt.bot != "" && !m.isBot(tt.bot) {
+				t.Errorf("%q not recognized as a bot", tt.bot)
+			}
+			if m.isBot(tt.human) {
+				t.Errorf("%q recognized as a bot", tt.human)
+			}
+		})
+	}
+}
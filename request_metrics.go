@@ -0,0 +1,140 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// requestKey identifiziert einen Zähler: Route (Methode + Pattern) und Statuscode.
+type requestKey struct {
+	route  string
+	status int
+}
+
+// requestMetrics zählt Requests pro Route und Statuscode.
+type requestMetrics struct {
+	mu     sync.Mutex
+	counts map[requestKey]int64
+}
+
+func newRequestMetrics() *requestMetrics {
+	return &requestMetrics{counts: map[requestKey]int64{}}
+}
+
+func (m *requestMetrics) inc(route string, status int) {
+	m.mu.Lock()
+	m.counts[requestKey{route: route, status: status}]++
+	m.mu.Unlock()
+}
+
+// Summe über alle Routen und Statuscodes
+func (m *requestMetrics) total() int64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var total int64
+	for _, n := range m.counts {
+		total += n
+	}
+	return total
+}
+
+// Kopie der Zähler, nach Route und Status sortiert
+func (m *requestMetrics) snapshot() ([]requestKey, map[requestKey]int64) {
+	m.mu.Lock()
+	counts := make(map[requestKey]int64, len(m.counts))
+	for k, n := range m.counts {
+		counts[k] = n
+	}
+	m.mu.Unlock()
+
+	keys := make([]requestKey, 0, len(counts))
+	for k := range counts {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].route != keys[j].route {
+			return keys[i].route < keys[j].route
+		}
+		return keys[i].status < keys[j].status
+	})
+	return keys, counts
+}
+
+func (m *requestMetrics) reset() {
+	m.mu.Lock()
+	m.counts = map[requestKey]int64{}
+	m.mu.Unlock()
+}
+
+// Liest METRICS_EXCLUDE (kommagetrennte Routen-Patterns wie "GET /api/chirps"), deren
+// Requests zusätzlich zu den Routen mit SkipRequestMetrics nicht gezählt werden.
+func metricsExcludeFromEnv(raw string) map[string]bool {
+	excluded := map[string]bool{}
+	for _, pattern := range strings.Split(raw, ",") {
+		if pattern = strings.TrimSpace(pattern); pattern != "" {
+			excluded[pattern] = true
+		}
+	}
+	return excluded
+}
+
+// Middleware: Zählt Requests einer Route nach Statuscode.
+func (cfg *apiConfig) middlewareCountRequests(route string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sw := &statusWriter{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(sw, r)
+		cfg.requestMetrics.inc(route, sw.status)
+	})
+}
+
+// Middleware für Routen ohne Request-Metriken: Sie werden nur auf Debug-Level geloggt.
+func middlewareSkipRequestMetrics(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if entry := logEntryFromContext(r.Context()); entry != nil {
+			entry.debugOnly = true
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// Handler für /metrics
+// Gibt die Zähler im Prometheus-Textformat aus.
+func (cfg *apiConfig) handlerPrometheus(w http.ResponseWriter, r *http.Request) {
+	var b strings.Builder
+	writeMetric := func(name, kind, help string) {
+		fmt.Fprintf(&b, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, kind)
+	}
+
+	writeMetric("chirpy_http_requests_total", "counter", "HTTP requests by route and status code.")
+	keys, counts := cfg.requestMetrics.snapshot()
+	for _, k := range keys {
+		fmt.Fprintf(&b, "chirpy_http_requests_total{route=%s,code=\"%d\"} %d\n", promLabel(k.route), k.status, counts[k])
+	}
+	writeMetric("chirpy_fileserver_hits_total", "counter", "Fileserver hits by humans.")
+	fmt.Fprintf(&b, "chirpy_fileserver_hits_total %d\n", cfg.fileserverHits.Load())
+	writeMetric("chirpy_fileserver_bot_hits_total", "counter", "Fileserver hits by bots.")
+	fmt.Fprintf(&b, "chirpy_fileserver_bot_hits_total %d\n", cfg.fileserverBotHits.Load())
+	writeMetric("chirpy_uptime_seconds", "gauge", "Seconds since the server started.")
+	fmt.Fprintf(&b, "chirpy_uptime_seconds %d\n", cfg.uptimeSeconds())
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write([]byte(b.String())); err != nil {
+		handleWriteError(err)
+	}
+}
+
+// Label-Wert im Prometheus-Format: in Anführungszeichen, \, " und Zeilenumbruch escaped.
+func promLabel(s string) string {
+	s = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s)
+	return `"` + s + `"`
+}
+
+// Sekunden seit dem Start des Servers; echte Zeit, unabhängig von cfg.clock
+func (cfg *apiConfig) uptimeSeconds() int64 {
+	return int64(time.Since(cfg.startedAt).Seconds())
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"maps"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+// /metrics ohne die Uptime, die sich zwischen zwei Abrufen ändern kann.
+func prometheusCounters(t *testing.T, ts *testServer) string {
+	t.Helper()
+	rec := ts.do(t, "GET", "/metrics", "", "")
+	expectStatus(t, rec, http.StatusOK)
+	var lines []string
+	for _, line := range strings.Split(rec.Body.String(), "\n") {
+		if !strings.HasPrefix(line, "chirpy_uptime_seconds ") {
+			lines = append(lines, line)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func requestCounts(ts *testServer) map[requestKey]int64 {
+	_, counts := ts.cfg.requestMetrics.snapshot()
+	return counts
+}
+
+// Health-Checks, Scrapes und per METRICS_EXCLUDE ausgenommene Routen lassen die Zähler unverändert.
+func TestRequestMetricsSkipProbes(t *testing.T) {
+	ts := newTestServer(t, func(cfg *apiConfig) {
+		cfg.metricsExclude = metricsExcludeFromEnv(" GET /api/chirps , ,GET /api/feed")
+	})
+	ts.createUser(t, "alice@example.com")
+	beforeCounts := requestCounts(ts)
+	beforeProm := prometheusCounters(t, ts)
+	if len(beforeCounts) == 0 {
+		t.Fatal("the signup was not counted")
+	}
+
+	for i := 0; i < 5; i++ {
+		expectStatus(t, ts.do(t, "GET", "/api/healthz", "", ""), http.StatusOK)
+		expectStatus(t, ts.do(t, "GET", "/api/readyz", "", ""), http.StatusOK)
+		expectStatus(t, ts.do(t, "GET", "/api/chirps", "", ""), http.StatusOK)
+		expectStatus(t, ts.do(t, "GET", "/admin/metrics", "", ""), http.StatusOK)
+		prometheusCounters(t, ts)
+	}
+
+	if got := requestCounts(ts); !maps.Equal(got, beforeCounts) {
+		t.Errorf("counters changed: %v, before %v", got, beforeCounts)
+	}
+	if got := prometheusCounters(t, ts); got != beforeProm {
+		t.Errorf("/metrics changed:\n%s\nbefore:\n%s", got, beforeProm)
+	}
+
+	// Andere Routen werden weiter gezählt
+	expectStatus(t, ts.do(t, "GET", "/api/chirps/"+uuid.NewString(), "", ""), http.StatusNotFound)
+	want := `chirpy_http_requests_total{route="GET /api/chirps/{chirpID}",code="404"} 1`
+	if got := prometheusCounters(t, ts); !strings.Contains(got, want) {
+		t.Errorf("/metrics is missing %s:\n%s", want, got)
+	}
+}
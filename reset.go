@@ -2,8 +2,30 @@ package main
 
 import "net/http"
 
+// Handler für /admin/reset
+// Nur bei PLATFORM=dev: setzt die Zugriffszähler (Menschen und Bots) zurück und löscht
+// alle User; Chirps und Refresh-Tokens werden per ON DELETE CASCADE mitgelöscht.
 func (cfg *apiConfig) handlerReset(w http.ResponseWriter, r *http.Request) {
+	if cfg.platform != "dev" {
+		respondWithError(w, http.StatusForbidden, "Reset is only allowed in dev environment", nil)
+		return
+	}
+
+	deletedUsers, err := cfg.db.DeleteAllUsers(r.Context())
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't delete users", err)
+		return
+	}
 	cfg.fileserverHits.Store(0)
-	w.WriteHeader(http.StatusOK)
-	w.Write([]byte("Hits reset to 0"))
+	cfg.fileserverBotHits.Store(0)
+	cfg.requestMetrics.reset()
+
+	type response struct {
+		DeletedUsers     int64 `json:"deleted_users"`
+		HitCountersReset bool  `json:"hit_counters_reset"`
+	}
+	respondWithJSON(w, http.StatusOK, response{
+		DeletedUsers:     deletedUsers,
+		HitCountersReset: true,
+	})
 }
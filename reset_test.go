@@ -0,0 +1,59 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"testing"
+)
+
+func TestResetDev(t *testing.T) {
+	ts := newTestServer(t)
+	_, token := ts.createUser(t, "alice@example.com")
+	ts.createUser(t, "bob@example.com")
+	ts.createChirp(t, token, "gone after reset")
+	ts.cfg.fileserverHits.Store(7)
+	ts.cfg.fileserverBotHits.Store(3)
+
+	rec := ts.do(t, "POST", "/admin/reset", "", "")
+	expectStatus(t, rec, http.StatusOK)
+	got := decodeResponse[struct {
+		DeletedUsers     int64 `json:"deleted_users"`
+		HitCountersReset bool  `json:"hit_counters_reset"`
+	}](t, rec)
+	if got.DeletedUsers != 2 || !got.HitCountersReset {
+		t.Errorf("response = %+v, want 2 deleted users and reset counters", got)
+	}
+
+	ctx := context.Background()
+	if users, _ := ts.store.GetUsers(ctx); len(users) != 0 {
+		t.Errorf("%d users left after reset", len(users))
+	}
+	if chirps, _ := ts.store.GetChirpsAsc(ctx); len(chirps) != 0 {
+		t.Errorf("%d chirps left after reset, want cascade delete", len(chirps))
+	}
+	if hits, bots := ts.cfg.fileserverHits.Load(), ts.cfg.fileserverBotHits.Load(); hits != 0 || bots != 0 {
+		t.Errorf("hit counters = %d/%d, want 0/0", hits, bots)
+	}
+}
+
+func TestResetOutsideDev(t *testing.T) {
+	ts := newTestServer(t, func(cfg *apiConfig) {
+		cfg.platform = "staging"
+		cfg.adminToken = "admin-secret"
+	})
+	ts.createUser(t, "alice@example.com")
+	ts.cfg.fileserverHits.Store(7)
+
+	// Auch mit gültigem Admin-Token: außerhalb von dev darf nichts gelöscht werden
+	rec := ts.do(t, "POST", "/admin/reset", "admin-secret", "")
+	expectStatus(t, rec, http.StatusForbidden)
+	if users, _ := ts.store.GetUsers(context.Background()); len(users) != 1 {
+		t.Errorf("%d users after forbidden reset, want 1", len(users))
+	}
+	if hits := ts.cfg.fileserverHits.Load(); hits != 7 {
+		t.Errorf("fileserverHits = %d after forbidden reset, want 7", hits)
+	}
+
+	// Ohne Admin-Zugang greift schon das Admin-Gate
+	expectStatus(t, ts.do(t, "POST", "/admin/reset", "", ""), http.StatusUnauthorized)
+}
@@ -0,0 +1,204 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// routeAuth beschreibt, welche Authentifizierung eine Route erwartet.
+// Der Nullwert bedeutet "nicht angegeben" und ist für /api-Routen nicht erlaubt.
+type routeAuth string
+
+const (
+	authPublic  routeAuth = "public"        // Keine Authentifizierung nötig
+	authUser    routeAuth = "user"          // Gültiges JWT-Access-Token nötig
+	authRefresh routeAuth = "refresh_token" // Refresh-Token im Authorization-Header, prüft der Handler
+	authAdmin   routeAuth = "admin"         // PLATFORM=dev, ADMIN_TOKEN oder User aus ADMIN_USER_IDS (middlewareAdmin)
+	authAPIKey  routeAuth = "api_key"       // "Authorization: ApiKey <key>", prüft der Handler
+)
+
+// routeOptions sammelt die Querschnittsthemen einer Route.
+type routeOptions struct {
+	Auth               routeAuth // Erwartete Authentifizierung
+	CountHits          bool      // Zugriffe im Fileserver-Zähler erfassen
+	SkipRequestMetrics bool      // Nicht in den Request-Zählern erfassen, nur Debug-Log (Health-Checks, Scrapes)
+	RateLimit          string    // Name des Limiters (rateLimitLogin, rateLimitChirp), leer für keinen
+	Description        string    // Kurzbeschreibung für /admin/routes
+}
+
+// route ist ein Eintrag der Routentabelle.
+type route struct {
+	Method  string // Leer für alle Methoden
+	Pattern string
+	Handler http.Handler
+	Options routeOptions
+}
+
+// Die Routentabelle des Servers.
+func (cfg *apiConfig) routes(filepathRoot string) []route {
+	routes := []route{
+		{"", "/app/", http.StripPrefix("/app", http.FileServer(http.Dir(filepathRoot))),
+			routeOptions{Auth: authPublic, CountHits: true, Description: "Static files"}},
+		{"GET", "/users/{userID}", http.HandlerFunc(cfg.handlerProfilePage),
+			routeOptions{Auth: authPublic, Description: "Public profile page (HTML)"}},
+
+		{"GET", "/api/healthz", http.HandlerFunc(handlerReadiness),
+			routeOptions{Auth: authPublic, SkipRequestMetrics: true, Description: "Liveness probe"}},
+		{"GET", "/api/readyz", http.HandlerFunc(cfg.handlerReadyz),
+			routeOptions{Auth: authPublic, SkipRequestMetrics: true, Description: "Readiness probe, pings the database"}},
+		{"POST", "/api/users", http.HandlerFunc(cfg.handlerCreateUser),
+			routeOptions{Auth: authPublic, Description: "Create a user"}},
+		{"GET", "/api/users/{userID}", http.HandlerFunc(cfg.handlerUserGet),
+			routeOptions{Auth: authPublic, Description: "Get a user by ID"}},
+		{"POST", "/api/users/{userID}/follow", http.HandlerFunc(cfg.handlerFollow),
+			routeOptions{Auth: authUser, Description: "Follow a user"}},
+		{"DELETE", "/api/users/{userID}/follow", http.HandlerFunc(cfg.handlerUnfollow),
+			routeOptions{Auth: authUser, Description: "Stop following a user"}},
+		{"GET", "/api/feed", http.HandlerFunc(cfg.handlerFeed),
+			routeOptions{Auth: authUser, Description: "Chirps from users you follow, newest first, paginated"}},
+		{"POST", "/api/login", http.HandlerFunc(cfg.handlerLogin),
+			routeOptions{Auth: authPublic, RateLimit: rateLimitLogin, Description: "Log in with email and password"}},
+		{"POST", "/api/refresh", http.HandlerFunc(cfg.handlerRefresh),
+			routeOptions{Auth: authRefresh, Description: "Exchange a refresh token (Bearer) for a new access token"}},
+		{"POST", "/api/revoke", http.HandlerFunc(cfg.handlerRevoke),
+			routeOptions{Auth: authRefresh, Description: "Revoke a refresh token (Bearer)"}},
+		{"POST", "/api/chirps", http.HandlerFunc(cfg.handlerCreateChirp),
+			routeOptions{Auth: authUser, RateLimit: rateLimitChirp, Description: "Create a chirp as the authenticated user"}},
+		{"GET", "/api/chirps", http.HandlerFunc(cfg.handlerChirpsGet),
+			routeOptions{Auth: authPublic, Description: "List all chirps"}},
+		{"GET", "/api/chirps/{chirpID}", http.HandlerFunc(cfg.handlerChirpGet),
+			routeOptions{Auth: authPublic, Description: "Get a chirp by ID or short ID"}},
+		{"PUT", "/api/chirps/{chirpID}", http.HandlerFunc(cfg.handlerChirpUpdate),
+			routeOptions{Auth: authUser, Description: "Edit the body of one of your own chirps"}},
+		{"DELETE", "/api/chirps/{chirpID}", http.HandlerFunc(cfg.handlerChirpDelete),
+			routeOptions{Auth: authUser, Description: "Delete one of your own chirps"}},
+		{"POST", "/api/chirps/{chirpID}/like", http.HandlerFunc(cfg.handlerChirpLike),
+			routeOptions{Auth: authUser, Description: "Like a chirp"}},
+		{"DELETE", "/api/chirps/{chirpID}/like", http.HandlerFunc(cfg.handlerChirpUnlike),
+			routeOptions{Auth: authUser, Description: "Remove your like from a chirp"}},
+		{"GET", "/api/chirps/{chirpID}/translate", http.HandlerFunc(cfg.handlerChirpTranslate),
+			routeOptions{Auth: authPublic, Description: "Translate a chirp, ?to=<language>"}},
+		{"PUT", "/api/users/me/profile", http.HandlerFunc(cfg.handlerProfileUpdate),
+			routeOptions{Auth: authUser, Description: "Set your display name and bio"}},
+		{"GET", "/api/users/me/templates", http.HandlerFunc(cfg.handlerTemplatesList),
+			routeOptions{Auth: authUser, Description: "List your chirp templates"}},
+		{"POST", "/api/users/me/templates", http.HandlerFunc(cfg.handlerTemplateCreate),
+			routeOptions{Auth: authUser, Description: "Create a chirp template"}},
+		{"GET", "/api/users/me/templates/{templateID}", http.HandlerFunc(cfg.handlerTemplateGet),
+			routeOptions{Auth: authUser, Description: "Get one of your chirp templates"}},
+		{"PUT", "/api/users/me/templates/{templateID}", http.HandlerFunc(cfg.handlerTemplateUpdate),
+			routeOptions{Auth: authUser, Description: "Replace name and body of a chirp template"}},
+		{"DELETE", "/api/users/me/templates/{templateID}", http.HandlerFunc(cfg.handlerTemplateDelete),
+			routeOptions{Auth: authUser, Description: "Delete a chirp template"}},
+		{"POST", "/api/polka/webhooks", http.HandlerFunc(cfg.handlerPolkaWebhook),
+			routeOptions{Auth: authAPIKey, Description: "Payment provider webhook (POLKA_KEY)"}},
+		{"GET", "/api/schemas/{file}", http.HandlerFunc(handlerSchemaGet),
+			routeOptions{Auth: authPublic, Description: "JSON Schema of a response type, e.g. chirp.json"}},
+
+		{"POST", "/admin/reset", http.HandlerFunc(cfg.handlerReset),
+			routeOptions{Auth: authAdmin, Description: "Dev only: reset hit counters and delete all users"}},
+		{"GET", "/admin/users", http.HandlerFunc(cfg.handlerAdminUsers),
+			routeOptions{Auth: authAdmin, Description: "List all users"}},
+		{"POST", "/admin/auth/diagnose", http.HandlerFunc(cfg.handlerAuthDiagnose),
+			routeOptions{Auth: authAdmin, Description: "Report which validation step a JWT fails"}},
+		{"GET", "/admin/metrics", http.HandlerFunc(cfg.handlerMetrics),
+			routeOptions{Auth: authAdmin, SkipRequestMetrics: true, Description: "Hit and request counters, HTML or JSON"}},
+		{"GET", "/metrics", http.HandlerFunc(cfg.handlerPrometheus),
+			routeOptions{Auth: authAdmin, SkipRequestMetrics: true, Description: "Counters in Prometheus text format"}},
+		{"GET", "/admin/routes", http.HandlerFunc(cfg.handlerRoutes),
+			routeOptions{Auth: authAdmin, Description: "This route table"}},
+	}
+	if cfg.platform == "dev" {
+		routes = append(routes,
+			route{"DELETE", "/admin/testing/users/{userID}", http.HandlerFunc(cfg.handlerTestingDeleteUser),
+				routeOptions{Auth: authAdmin, Description: "Dev only: hard delete one user and their chirps"}},
+			route{"DELETE", "/admin/testing/chirps", http.HandlerFunc(cfg.handlerTestingDeleteChirps),
+				routeOptions{Auth: authAdmin, Description: "Dev only: delete all chirps"}},
+			route{"POST", "/admin/testing/time-travel", http.HandlerFunc(cfg.handlerTestingTimeTravel),
+				routeOptions{Auth: authAdmin, Description: "Dev only: shift the server clock"}},
+		)
+	}
+	if _, ok := cfg.signupChallenge.(*powChallenge); ok {
+		routes = append(routes, route{"GET", "/api/signup/challenge", http.HandlerFunc(cfg.handlerSignupChallenge),
+			routeOptions{Auth: authPublic, Description: "Issue a proof-of-work signup challenge"}})
+	}
+	return routes
+}
+
+// Globale Middleware-Kette um den Mux, wie sie der Server ausliefert.
+func (cfg *apiConfig) serverHandler(mux http.Handler) http.Handler {
+	return cfg.middlewareLogging(middlewareStripBasePath(cfg.basePath, middlewareNormalizeAPIPath(middlewareGzipRequest(mux))))
+}
+
+// Registriert alle Routen am Mux und baut für jede die passende Middleware-Kette.
+// Jede /api-Route muss ihre Authentifizierung explizit angeben.
+func (cfg *apiConfig) registerRoutes(mux *http.ServeMux, routes []route) error {
+	for _, rt := range routes {
+		if strings.HasPrefix(rt.Pattern, "/api/") && rt.Options.Auth == "" {
+			return fmt.Errorf("route %s %s does not declare its auth requirement", rt.Method, rt.Pattern)
+		}
+		handler := rt.Handler
+		if rt.Options.RateLimit != "" {
+			limiter, ok := cfg.rateLimiters[rt.Options.RateLimit]
+			if !ok {
+				return fmt.Errorf("route %s %s uses unknown rate limit %q", rt.Method, rt.Pattern, rt.Options.RateLimit)
+			}
+			// Innerhalb von middlewareAuth, damit nach User statt nach IP begrenzt wird
+			if limiter != nil {
+				handler = cfg.middlewareRateLimit(limiter, handler)
+			}
+		}
+		switch rt.Options.Auth {
+		case authUser:
+			handler = cfg.middlewareAuth(handler)
+		case authAdmin:
+			handler = cfg.middlewareAdmin(handler)
+		}
+		if rt.Options.CountHits {
+			handler = cfg.middlewareMetricsInc(handler)
+		}
+		pattern := strings.TrimSpace(rt.Method + " " + rt.Pattern)
+		if rt.Options.SkipRequestMetrics || cfg.metricsExclude[pattern] {
+			handler = middlewareSkipRequestMetrics(handler)
+		} else {
+			handler = cfg.middlewareCountRequests(pattern, handler)
+		}
+		mux.Handle(pattern, handler)
+	}
+	cfg.routeTable = routes
+	return nil
+}
+
+// Meldet, ob die Routentabelle Routen mit User-Authentifizierung enthält.
+func (cfg *apiConfig) hasAuthRoutes() bool {
+	for _, rt := range cfg.routeTable {
+		if rt.Options.Auth == authUser {
+			return true
+		}
+	}
+	return false
+}
+
+// Handler für /admin/routes
+// Gibt die registrierte Routentabelle als JSON zurück.
+func (cfg *apiConfig) handlerRoutes(w http.ResponseWriter, r *http.Request) {
+	type routeInfo struct {
+		Method      string    `json:"method,omitempty"`
+		Pattern     string    `json:"pattern"`
+		Auth        routeAuth `json:"auth"`
+		RateLimit   string    `json:"rate_limit,omitempty"`
+		Description string    `json:"description,omitempty"`
+	}
+	infos := make([]routeInfo, 0, len(cfg.routeTable))
+	for _, rt := range cfg.routeTable {
+		infos = append(infos, routeInfo{
+			Method:      rt.Method,
+			Pattern:     rt.Pattern,
+			Auth:        rt.Options.Auth,
+			RateLimit:   rt.Options.RateLimit,
+			Description: rt.Options.Description,
+		})
+	}
+	respondWithJSON(w, http.StatusOK, infos)
+}
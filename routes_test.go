@@ -0,0 +1,157 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nuke87/go_http_server/internal/auth"
+)
+
+var knownRouteAuth = map[routeAuth]bool{
+	authPublic: true, authUser: true, authRefresh: true, authAdmin: true, authAPIKey: true,
+}
+
+// Alle Varianten der Routentabelle: dev (mit Test-Routen) und Produktion, mit Signup-Challenge.
+func allRouteTables(t *testing.T) map[string][]route {
+	t.Helper()
+	challenge, err := newPowChallenge(1, time.Now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tables := map[string][]route{}
+	for _, platform := range []string{"dev", "production"} {
+		cfg := &apiConfig{platform: platform, signupChallenge: challenge}
+		tables[platform] = cfg.routes(t.TempDir())
+	}
+	return tables
+}
+
+func TestRoutesDeclareAuth(t *testing.T) {
+	for platform, routes := range allRouteTables(t) {
+		for _, rt := range routes {
+			name := platform + ": " + strings.TrimSpace(rt.Method+" "+rt.Pattern)
+			if strings.HasPrefix(rt.Pattern, "/api/") && !knownRouteAuth[rt.Options.Auth] {
+				t.Errorf("%s declares auth %q, want one of the routeAuth constants", name, rt.Options.Auth)
+			}
+			if (strings.HasPrefix(rt.Pattern, "/admin/") || rt.Pattern == "/metrics") && rt.Options.Auth != authAdmin {
+				t.Errorf("%s is an admin route but declares auth %q", name, rt.Options.Auth)
+			}
+			if rt.Options.Description == "" {
+				t.Errorf("%s has no description for /admin/routes", name)
+			}
+		}
+	}
+}
+
+func TestRegisterRoutesRejectsMissingAuth(t *testing.T) {
+	cfg := &apiConfig{}
+	err := cfg.registerRoutes(http.NewServeMux(), []route{
+		{"GET", "/api/undeclared", http.NotFoundHandler(), routeOptions{}},
+	})
+	if err == nil || !strings.Contains(err.Error(), "/api/undeclared") {
+		t.Errorf("err = %v, want an error naming the route", err)
+	}
+}
+
+func TestAdminRoutesListing(t *testing.T) {
+	ts := newTestServer(t)
+	rec := ts.do(t, "GET", "/admin/routes", "", "")
+	expectStatus(t, rec, http.StatusOK)
+	got := decodeResponse[[]struct {
+		Method  string    `json:"method"`
+		Pattern string    `json:"pattern"`
+		Auth    routeAuth `json:"auth"`
+	}](t, rec)
+	if len(got) != len(ts.cfg.routeTable) {
+		t.Fatalf("listed %d routes, table has %d", len(got), len(ts.cfg.routeTable))
+	}
+	for i, rt := range ts.cfg.routeTable {
+		if got[i].Method != rt.Method || got[i].Pattern != rt.Pattern || got[i].Auth != rt.Options.Auth {
+			t.Errorf("entry %d = %+v, want %s %s (%s)", i, got[i], rt.Method, rt.Pattern, rt.Options.Auth)
+		}
+	}
+}
+
+// Außerhalb von dev lässt das Admin-Gate jede Admin-Route nur mit Zugangsdaten durch.
+func TestAdminGateOnEveryAdminRoute(t *testing.T) {
+	ts := newTestServer(t, func(cfg *apiConfig) { cfg.platform = "production" })
+	for _, rt := range ts.cfg.routeTable {
+		if rt.Options.Auth != authAdmin {
+			continue
+		}
+		path := strings.ReplaceAll(rt.Pattern, "{userID}", uuid.NewString())
+		rec := ts.do(t, rt.Method, path, "", "")
+		if rec.Code != http.StatusUnauthorized {
+			t.Errorf("%s %s without credentials: status %d, want 401", rt.Method, path, rec.Code)
+		}
+	}
+}
+
+func TestMiddlewareAdmin(t *testing.T) {
+	adminID, userID := uuid.New(), uuid.New()
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	jwt := func(id uuid.UUID, issued time.Time) string {
+		token, err := auth.MakeJWT(id, testJWTSecret, issued, time.Hour)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return "Bearer " + token
+	}
+
+	tests := []struct {
+		name          string
+		platform      string
+		authorization string
+		want          int
+	}{
+		{"dev is open", "dev", "", http.StatusOK},
+		{"no credentials", "production", "", http.StatusUnauthorized},
+		{"admin token", "production", "Bearer admin-secret", http.StatusOK},
+		{"wrong admin token", "production", "Bearer admin-secret2", http.StatusForbidden},
+		{"admin token with other scheme", "production", "ApiKey admin-secret", http.StatusForbidden},
+		{"admin user", "production", jwt(adminID, now), http.StatusOK},
+		{"normal user", "production", jwt(userID, now), http.StatusForbidden},
+		{"expired admin user token", "production", jwt(adminID, now.Add(-2*time.Hour)), http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &apiConfig{
+				platform:     tt.platform,
+				adminToken:   "admin-secret",
+				adminUserIDs: map[uuid.UUID]bool{adminID: true},
+				jwtSecret:    testJWTSecret,
+			}
+			cfg.clock.now = func() time.Time { return now }
+			h := cfg.middlewareAdmin(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			}))
+			req := httptest.NewRequest("GET", "/admin/metrics", nil)
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+			if rec.Code != tt.want {
+				t.Errorf("status = %d, want %d", rec.Code, tt.want)
+			}
+		})
+	}
+}
+
+// Mit Zugangsdaten erreichen Requests außerhalb von dev die Admin-Handler.
+func TestAdminRoutesWithCredentials(t *testing.T) {
+	admin := uuid.New()
+	ts := newTestServer(t, func(cfg *apiConfig) {
+		cfg.platform = "production"
+		cfg.adminToken = "admin-secret"
+		cfg.adminUserIDs = map[uuid.UUID]bool{admin: true}
+	})
+	for _, token := range []string{"admin-secret", ts.token(t, admin)} {
+		expectStatus(t, ts.do(t, "GET", "/admin/routes", token, ""), http.StatusOK)
+		expectStatus(t, ts.do(t, "GET", "/admin/users", token, ""), http.StatusOK)
+	}
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"crypto/rand"
+	"strings"
+)
+
+const (
+	shortIDLength     = 8
+	shortIDMaxRetries = 5
+	shortIDAlphabet   = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+)
+
+// Erzeugt eine zufällige, URL-sichere Base62-ID für Chirp-Permalinks.
+func newShortID() (string, error) {
+	// 248 ist das größte Vielfache von 62 unter 256; größere Bytes werden verworfen,
+	// damit alle Zeichen gleich wahrscheinlich sind.
+	const maxByte = 256 - (256 % len(shortIDAlphabet))
+
+	id := make([]byte, 0, shortIDLength)
+	buf := make([]byte, shortIDLength*2)
+	for len(id) < shortIDLength {
+		if _, err := rand.Read(buf); err != nil {
+			return "", err
+		}
+		for _, b := range buf {
+			if int(b) >= maxByte {
+				continue
+			}
+			id = append(id, shortIDAlphabet[int(b)%len(shortIDAlphabet)])
+			if len(id) == shortIDLength {
+				break
+			}
+		}
+	}
+	return string(id), nil
+}
+
+// Prüft, ob ein String das Format einer Short-ID hat (Länge und Alphabet). Auch die
+// Hex-IDs, mit denen Migration 003 ältere Chirps befüllt hat, fallen darunter.
+func isShortID(s string) bool {
+	if len(s) != shortIDLength {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if strings.IndexByte(shortIDAlphabet, s[i]) < 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Meldet, ob err eine Verletzung des Unique-Index auf chirps.short_id ist.
+func isShortIDCollision(err error) bool {
+	return isUniqueViolation(err, "chirps_short_id_idx")
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/nuke87/go_http_server/internal/database"
+	"github.com/nuke87/go_http_server/pkg/chirpy"
+)
+
+func TestNewShortID(t *testing.T) {
+	seen := map[string]bool{}
+	for i := 0; i < 1000; i++ {
+		id, err := newShortID()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !isShortID(id) {
+			t.Fatalf("newShortID() = %q is not a short ID", id)
+		}
+		if seen[id] {
+			t.Fatalf("duplicate short ID %q", id)
+		}
+		seen[id] = true
+	}
+	// Hex-IDs aus Migration 003 bleiben gültig
+	for id, want := range map[string]bool{"0a1b2c3d": true, "aZ09bY18": true, "abc": false, "abcdefg-": false, "abcdefghi": false} {
+		if got := isShortID(id); got != want {
+			t.Errorf("isShortID(%q) = %v, want %v", id, got, want)
+		}
+	}
+}
+
+// collidingStore gibt den ersten collisions Chirps die Short-ID eines vorhandenen Chirps,
+// so dass der memoryStore eine echte Kollision meldet.
+type collidingStore struct {
+	*memoryStore
+	takenShortID string
+	collisions   int
+	shortIDs     []string // Short-IDs aller Versuche, wie sie der Handler erzeugt hat
+}
+
+func (s *collidingStore) CreateChirp(ctx context.Context, arg database.CreateChirpParams) (database.Chirp, error) {
+	s.shortIDs = append(s.shortIDs, arg.ShortID)
+	if len(s.shortIDs) <= s.collisions {
+		arg.ShortID = s.takenShortID
+	}
+	return s.memoryStore.CreateChirp(ctx, arg)
+}
+
+func TestCreateChirpShortIDCollision(t *testing.T) {
+	tests := []struct {
+		name       string
+		collisions int
+		status     int
+	}{
+		{"retried with a new ID", 2, http.StatusCreated},
+		{"gives up after shortIDMaxRetries", shortIDMaxRetries, http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &collidingStore{collisions: tt.collisions}
+			ts := newTestServer(t, func(cfg *apiConfig) { cfg.db = store })
+			store.memoryStore = ts.store
+			_, token := ts.createUser(t, "alice@example.com")
+			existing := ts.createChirp(t, token, "first")
+			store.takenShortID = existing.ShortID
+			store.shortIDs = nil
+
+			rec := ts.do(t, "POST", "/api/chirps", token, `{"body":"second"}`)
+			expectStatus(t, rec, tt.status)
+			if tt.status != http.StatusCreated {
+				if len(store.shortIDs) != shortIDMaxRetries {
+					t.Errorf("%d attempts, want %d", len(store.shortIDs), shortIDMaxRetries)
+				}
+				return
+			}
+			got := decodeResponse[chirpy.Chirp](t, rec)
+			if len(store.shortIDs) != tt.collisions+1 {
+				t.Errorf("%d attempts, want %d", len(store.shortIDs), tt.collisions+1)
+			}
+			if got.ShortID == existing.ShortID || got.ShortID != store.shortIDs[len(store.shortIDs)-1] {
+				t.Errorf("short_id = %q, existing %q, attempts %q", got.ShortID, existing.ShortID, store.shortIDs)
+			}
+			for i := 1; i < len(store.shortIDs); i++ {
+				if store.shortIDs[i] == store.shortIDs[i-1] {
+					t.Errorf("attempt %d reused short ID %q", i+1, store.shortIDs[i])
+				}
+			}
+		})
+	}
+}
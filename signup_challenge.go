@@ -0,0 +1,263 @@
+package main
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/bits"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// signupProof enthält die Felder, mit denen ein Client beim Signup eine Challenge löst.
+type signupProof struct {
+	Challenge    string `json:"challenge"`     // Proof-of-Work: vom Server ausgegebene Challenge
+	Nonce        string `json:"nonce"`         // Proof-of-Work: vom Client gefundene Nonce
+	CaptchaToken string `json:"captcha_token"` // CAPTCHA: Token des CAPTCHA-Widgets
+}
+
+// SignupChallenge prüft beim Anlegen eines Users, dass kein Bot am Werk ist.
+type SignupChallenge interface {
+	// ErrorCode ist der Fehlercode, mit dem eine fehlgeschlagene Prüfung beantwortet wird.
+	ErrorCode() string
+	// Verify liefert einen Fehler, wenn der Nachweis fehlt oder ungültig ist. Ein gültiger
+	// Nachweis gilt danach als verbraucht, bis Release ihn wieder freigibt.
+	Verify(ctx context.Context, proof signupProof, clientIP string) error
+	// Release gibt einen mit Verify verbrauchten Nachweis frei, wenn das Anlegen des Users
+	// danach scheitert, damit der Client ihn erneut einreichen kann.
+	Release(proof signupProof)
+}
+
+// Liest SIGNUP_CHALLENGE (off, pow, captcha) und baut die passende Implementierung.
+// Standard ist off (nil). now ist die Uhr des Servers (cfg.clock.Now).
+func signupChallengeFromEnv(now func() time.Time) (SignupChallenge, error) {
+	switch mode := os.Getenv("SIGNUP_CHALLENGE"); mode {
+	case "", "off":
+		return nil, nil
+	case "pow":
+		difficulty := 20
+		if raw := os.Getenv("SIGNUP_POW_DIFFICULTY"); raw != "" {
+			d, err := strconv.Atoi(raw)
+			if err != nil || d < 1 || d > 32 {
+				return nil, fmt.Errorf("SIGNUP_POW_DIFFICULTY must be between 1 and 32, got %q", raw)
+			}
+			difficulty = d
+		}
+		return newPowChallenge(difficulty, now)
+	case "captcha":
+		verifyURL := os.Getenv("SIGNUP_CAPTCHA_VERIFY_URL")
+		if verifyURL == "" {
+			return nil, errors.New("SIGNUP_CAPTCHA_VERIFY_URL must be set when SIGNUP_CHALLENGE=captcha")
+		}
+		return &captchaChallenge{
+			verifyURL: verifyURL,
+			secret:    os.Getenv("SIGNUP_CAPTCHA_SECRET"),
+			client:    &http.Client{Timeout: 5 * time.Second},
+		}, nil
+	default:
+		return nil, fmt.Errorf("unknown SIGNUP_CHALLENGE %q (want off, pow or captcha)", mode)
+	}
+}
+
+// powChallenge ist ein Hashcash-artiger Proof-of-Work: sha256(challenge + ":" + nonce)
+// muss mit mindestens difficulty Null-Bits beginnen. Challenges sind per HMAC signiert
+// und zustandslos; verbrauchte Challenges werden bis zu ihrem Ablauf gemerkt.
+type powChallenge struct {
+	difficulty int
+	secret     []byte
+	ttl        time.Duration
+	now        func() time.Time
+
+	mu   sync.Mutex
+	used map[string]time.Time
+}
+
+func newPowChallenge(difficulty int, now func() time.Time) (*powChallenge, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, err
+	}
+	return &powChallenge{
+		difficulty: difficulty,
+		secret:     secret,
+		ttl:        10 * time.Minute,
+		now:        now,
+		used:       map[string]time.Time{},
+	}, nil
+}
+
+func (p *powChallenge) ErrorCode() string {
+	return "pow_failed"
+}
+
+// Erzeugt eine neue Challenge mit Ablaufzeit.
+func (p *powChallenge) issue(now time.Time) (string, time.Time, error) {
+	expiresAt := now.Add(p.ttl)
+	payload := make([]byte, 24)
+	if _, err := rand.Read(payload[:16]); err != nil {
+		return "", time.Time{}, err
+	}
+	binary.BigEndian.PutUint64(payload[16:], uint64(expiresAt.Unix()))
+	enc := base64.RawURLEncoding.EncodeToString(payload)
+	return enc + "." + p.sign(enc), expiresAt, nil
+}
+
+func (p *powChallenge) sign(payload string) string {
+	mac := hmac.New(sha256.New, p.secret)
+	mac.Write([]byte(payload))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func (p *powChallenge) Verify(ctx context.Context, proof signupProof, clientIP string) error {
+	if proof.Challenge == "" || proof.Nonce == "" {
+		return errors.New("challenge and nonce are required")
+	}
+	payload, sig, ok := strings.Cut(proof.Challenge, ".")
+	if !ok || !hmac.Equal([]byte(sig), []byte(p.sign(payload))) {
+		return errors.New("invalid challenge")
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(payload)
+	if err != nil || len(raw) != 24 {
+		return errors.New("invalid challenge")
+	}
+	now := p.now()
+	expiresAt := time.Unix(int64(binary.BigEndian.Uint64(raw[16:])), 0)
+	if now.After(expiresAt) {
+		return errors.New("challenge expired")
+	}
+
+	sum := sha256.Sum256([]byte(proof.Challenge + ":" + proof.Nonce))
+	if leadingZeroBits(sum[:]) < p.difficulty {
+		return errors.New("nonce does not meet the difficulty")
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for c, exp := range p.used {
+		if now.After(exp) {
+			delete(p.used, c)
+		}
+	}
+	if _, seen := p.used[proof.Challenge]; seen {
+		return errors.New("challenge already used")
+	}
+	p.used[proof.Challenge] = expiresAt
+	return nil
+}
+
+func (p *powChallenge) Release(proof signupProof) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	delete(p.used, proof.Challenge)
+}
+
+func leadingZeroBits(b []byte) int {
+	n := 0
+	for _, x := range b {
+		if x != 0 {
+			return n + bits.LeadingZeros8(x)
+		}
+		n += 8
+	}
+	return n
+}
+
+// captchaChallenge prüft ein CAPTCHA-Token bei einem externen Dienst. Das Format
+// (POST mit secret, response, remoteip; Antwort {"success": bool}) entspricht
+// reCAPTCHA, hCaptcha und Turnstile.
+type captchaChallenge struct {
+	verifyURL string
+	secret    string
+	client    *http.Client
+}
+
+func (c *captchaChallenge) ErrorCode() string {
+	return "captcha_failed"
+}
+
+func (c *captchaChallenge) Verify(ctx context.Context, proof signupProof, clientIP string) error {
+	if proof.CaptchaToken == "" {
+		return errors.New("captcha_token is required")
+	}
+	form := url.Values{
+		"secret":   {c.secret},
+		"response": {proof.CaptchaToken},
+	}
+	if clientIP != "" {
+		form.Set("remoteip", clientIP)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.verifyURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("captcha verification request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("captcha verifier returned %s", resp.Status)
+	}
+	var result struct {
+		Success bool `json:"success"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return fmt.Errorf("couldn't decode captcha verifier response: %w", err)
+	}
+	if !result.Success {
+		return errors.New("captcha rejected")
+	}
+	return nil
+}
+
+// Ob ein Token mehrfach gilt, entscheidet der externe Dienst; hier gibt es nichts freizugeben.
+func (c *captchaChallenge) Release(proof signupProof) {}
+
+// Handler für /api/signup/challenge (GET)
+// Gibt eine neue Proof-of-Work-Challenge aus. Nur registriert, wenn SIGNUP_CHALLENGE=pow.
+func (cfg *apiConfig) handlerSignupChallenge(w http.ResponseWriter, r *http.Request) {
+	pow, ok := cfg.signupChallenge.(*powChallenge)
+	if !ok {
+		respondWithError(w, http.StatusNotFound, "Not found", nil)
+		return
+	}
+	challenge, expiresAt, err := pow.issue(cfg.clock.Now())
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't create challenge", err)
+		return
+	}
+	type response struct {
+		Challenge  string    `json:"challenge"`
+		Difficulty int       `json:"difficulty"`
+		Algorithm  string    `json:"algorithm"`
+		ExpiresAt  time.Time `json:"expires_at"`
+	}
+	respondWithJSON(w, http.StatusOK, response{
+		Challenge:  challenge,
+		Difficulty: pow.difficulty,
+		Algorithm:  "sha256(challenge:nonce), leading zero bits",
+		ExpiresAt:  expiresAt,
+	})
+}
+
+// IP-Adresse des Clients aus RemoteAddr, ohne Port.
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"crypto/sha256"
+	"net/http"
+	"strconv"
+	"testing"
+)
+
+// Holt eine Challenge über GET /api/signup/challenge und sucht eine passende Nonce.
+func solvePowChallenge(t *testing.T, ts *testServer) signupProof {
+	t.Helper()
+	rec := ts.do(t, "GET", "/api/signup/challenge", "", "")
+	expectStatus(t, rec, http.StatusOK)
+	got := decodeResponse[struct {
+		Challenge  string `json:"challenge"`
+		Difficulty int    `json:"difficulty"`
+	}](t, rec)
+	for i := 0; i < 1<<20; i++ {
+		nonce := strconv.Itoa(i)
+		sum := sha256.Sum256([]byte(got.Challenge + ":" + nonce))
+		if leadingZeroBits(sum[:]) >= got.Difficulty {
+			return signupProof{Challenge: got.Challenge, Nonce: nonce}
+		}
+	}
+	t.Fatal("no nonce found")
+	return signupProof{}
+}
+
+func signupBody(email string, proof signupProof) string {
+	return `{"email":"` + email + `","password":"hunter22","challenge":"` + proof.Challenge + `","nonce":"` + proof.Nonce + `"}`
+}
+
+// Eine Challenge wird erst verbraucht, wenn der User wirklich angelegt ist.
+func TestSignupChallengeReleasedOnFailure(t *testing.T) {
+	ts := newTestServer(t, func(cfg *apiConfig) {
+		challenge, err := newPowChallenge(8, cfg.clock.Now)
+		if err != nil {
+			t.Fatal(err)
+		}
+		cfg.signupChallenge = challenge
+	})
+
+	expectStatus(t, ts.do(t, "POST", "/api/users", "", `{"email":"alice@example.com","password":"hunter22"}`), http.StatusForbidden)
+	expectStatus(t, ts.do(t, "POST", "/api/users", "", signupBody("alice@example.com", solvePowChallenge(t, ts))), http.StatusCreated)
+
+	proof := solvePowChallenge(t, ts)
+	expectStatus(t, ts.do(t, "POST", "/api/users", "", signupBody("alice@example.com", proof)), http.StatusConflict)
+	expectStatus(t, ts.do(t, "POST", "/api/users", "", signupBody("bob@example.com", proof)), http.StatusCreated)
+	expectStatus(t, ts.do(t, "POST", "/api/users", "", signupBody("carol@example.com", proof)), http.StatusForbidden)
+
+	bad := proof
+	bad.Challenge += "x"
+	expectStatus(t, ts.do(t, "POST", "/api/users", "", signupBody("carol@example.com", bad)), http.StatusForbidden)
+}
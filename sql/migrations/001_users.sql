@@ -1,10 +1,37 @@
 -- name: CreateUser :one
-INSERT INTO users (id, created_at, updated_at, email)
-VALUES (
-    gen_random_uuid(),
-    NOW(),
-    NOW(),
-    $1
-)
+INSERT INTO users (id, created_at, updated_at, email, hashed_password)
+VALUES ($1, $2, $3, $4, $5)
 RETURNING *;
 
+-- name: GetUserByEmail :one
+SELECT * FROM users
+WHERE email = $1;
+
+-- name: DeleteUser :execrows
+DELETE FROM users
+WHERE id = $1;
+
+-- name: DeleteAllUsers :execrows
+DELETE FROM users;
+
+-- name: UpgradeUserToChirpyRed :execrows
+UPDATE users SET is_chirpy_red = TRUE, updated_at = $2
+WHERE id = $1;
+
+-- name: GetUserByID :one
+SELECT * FROM users
+WHERE id = $1;
+
+-- name: GetUsers :many
+SELECT * FROM users
+ORDER BY created_at ASC, id ASC;
+
+-- name: UpdateUserProfile :one
+UPDATE users SET display_name = $2, bio = $3, updated_at = $4
+WHERE id = $1
+RETURNING *;
+
+-- name: LockUserForUpdate :one
+SELECT id FROM users
+WHERE id = $1
+FOR UPDATE;
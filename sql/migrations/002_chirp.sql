@@ -1,4 +1,82 @@
 -- name: CreateChirp :one
-INSERT INTO chirps (id, created_at, updated_at, body, user_id)
-VALUES ($1, $2, $3, $4, $5)
-RETURNING id, created_at, updated_at, body, user_id;
+INSERT INTO chirps (id, created_at, updated_at, body, user_id, short_id, masked_ranges)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+RETURNING *;
+
+-- name: GetChirpByShortID :one
+SELECT * FROM chirps
+WHERE short_id = $1;
+
+-- name: GetChirpsAsc :many
+SELECT * FROM chirps
+ORDER BY created_at, id;
+
+-- name: GetChirpsDesc :many
+SELECT * FROM chirps
+ORDER BY created_at DESC, id DESC;
+
+-- name: GetChirpByID :one
+SELECT * FROM chirps
+WHERE id = $1;
+
+-- name: DeleteAllChirps :execrows
+DELETE FROM chirps;
+
+-- name: DeleteChirpsByUser :execrows
+DELETE FROM chirps
+WHERE user_id = $1;
+
+-- name: DeleteChirp :exec
+DELETE FROM chirps
+WHERE id = $1;
+
+-- name: GetChirpsByAuthorAsc :many
+SELECT * FROM chirps
+WHERE user_id = $1
+ORDER BY created_at, id;
+
+-- name: GetChirpsByAuthorDesc :many
+SELECT * FROM chirps
+WHERE user_id = $1
+ORDER BY created_at DESC, id DESC;
+
+-- name: GetChirpsPageAsc :many
+SELECT * FROM chirps
+WHERE (created_at, id) > (sqlc.arg(cursor_created_at)::timestamp, sqlc.arg(cursor_id)::uuid)
+  AND (sqlc.narg(author_id)::uuid IS NULL OR user_id = sqlc.narg(author_id))
+  AND (sqlc.narg(liked_by)::uuid IS NULL OR EXISTS (
+    SELECT 1 FROM chirp_likes WHERE chirp_likes.chirp_id = chirps.id AND chirp_likes.user_id = sqlc.narg(liked_by)
+  ))
+ORDER BY created_at, id
+LIMIT sqlc.arg(page_limit);
+
+-- name: GetChirpsPageDesc :many
+SELECT * FROM chirps
+WHERE (created_at, id) < (sqlc.arg(cursor_created_at)::timestamp, sqlc.arg(cursor_id)::uuid)
+  AND (sqlc.narg(author_id)::uuid IS NULL OR user_id = sqlc.narg(author_id))
+  AND (sqlc.narg(liked_by)::uuid IS NULL OR EXISTS (
+    SELECT 1 FROM chirp_likes WHERE chirp_likes.chirp_id = chirps.id AND chirp_likes.user_id = sqlc.narg(liked_by)
+  ))
+ORDER BY created_at DESC, id DESC
+LIMIT sqlc.arg(page_limit);
+
+-- name: GetChirpsLikedByAsc :many
+SELECT * FROM chirps
+WHERE EXISTS (
+    SELECT 1 FROM chirp_likes WHERE chirp_likes.chirp_id = chirps.id AND chirp_likes.user_id = sqlc.arg(liked_by)
+  )
+  AND (sqlc.narg(author_id)::uuid IS NULL OR user_id = sqlc.narg(author_id))
+ORDER BY created_at, id;
+
+-- name: GetChirpsLikedByDesc :many
+SELECT * FROM chirps
+WHERE EXISTS (
+    SELECT 1 FROM chirp_likes WHERE chirp_likes.chirp_id = chirps.id AND chirp_likes.user_id = sqlc.arg(liked_by)
+  )
+  AND (sqlc.narg(author_id)::uuid IS NULL OR user_id = sqlc.narg(author_id))
+ORDER BY created_at DESC, id DESC;
+
+-- name: UpdateChirp :one
+UPDATE chirps SET body = $2, masked_ranges = $3, updated_at = $4
+WHERE id = $1
+RETURNING *;
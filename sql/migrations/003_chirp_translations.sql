@@ -0,0 +1,12 @@
+-- name: GetChirpTranslation :one
+SELECT * FROM chirp_translations
+WHERE chirp_id = $1 AND lang = $2;
+
+-- name: CreateChirpTranslation :exec
+INSERT INTO chirp_translations (chirp_id, lang, translated_body, source_lang, provider, created_at)
+VALUES ($1, $2, $3, $4, $5, NOW())
+ON CONFLICT (chirp_id, lang) DO NOTHING;
+
+-- name: DeleteChirpTranslations :exec
+DELETE FROM chirp_translations
+WHERE chirp_id = $1;
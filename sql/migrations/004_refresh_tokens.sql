@@ -0,0 +1,15 @@
+-- name: CreateRefreshToken :one
+INSERT INTO refresh_tokens (token, created_at, updated_at, user_id, expires_at, revoked_at)
+VALUES ($1, $2, $2, $3, $4, NULL)
+RETURNING *;
+
+-- name: GetUserFromRefreshToken :one
+SELECT users.* FROM users
+JOIN refresh_tokens ON users.id = refresh_tokens.user_id
+WHERE refresh_tokens.token = $1
+  AND refresh_tokens.revoked_at IS NULL
+  AND refresh_tokens.expires_at > $2;
+
+-- name: RevokeRefreshToken :exec
+UPDATE refresh_tokens SET revoked_at = $2, updated_at = $2
+WHERE token = $1;
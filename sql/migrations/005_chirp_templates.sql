@@ -0,0 +1,26 @@
+-- name: CreateChirpTemplate :one
+INSERT INTO chirp_templates (id, created_at, updated_at, user_id, name, body)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING *;
+
+-- name: GetChirpTemplate :one
+SELECT * FROM chirp_templates
+WHERE id = $1 AND user_id = $2;
+
+-- name: GetChirpTemplatesByUser :many
+SELECT * FROM chirp_templates
+WHERE user_id = $1
+ORDER BY created_at ASC, id ASC;
+
+-- name: CountChirpTemplatesByUser :one
+SELECT count(*) FROM chirp_templates
+WHERE user_id = $1;
+
+-- name: UpdateChirpTemplate :one
+UPDATE chirp_templates SET name = $3, body = $4, updated_at = $5
+WHERE id = $1 AND user_id = $2
+RETURNING *;
+
+-- name: DeleteChirpTemplate :execrows
+DELETE FROM chirp_templates
+WHERE id = $1 AND user_id = $2;
@@ -0,0 +1,12 @@
+-- +goose Up
+ALTER TABLE chirps ADD COLUMN short_id TEXT;
+-- Bestehende Chirps bekommen hier Hex-IDs (8 Zeichen aus md5, 32 Bit), neue Chirps
+-- Base62-IDs aus newShortID. Hex ist eine Teilmenge des Base62-Alphabets, die alten IDs
+-- bleiben also gültige Short-IDs; sie werden nicht umgeschrieben, damit Permalinks halten.
+UPDATE chirps SET short_id = substr(md5(random()::text || id::text), 1, 8) WHERE short_id IS NULL;
+ALTER TABLE chirps ALTER COLUMN short_id SET NOT NULL;
+CREATE UNIQUE INDEX chirps_short_id_idx ON chirps (short_id);
+
+-- +goose Down
+DROP INDEX chirps_short_id_idx;
+ALTER TABLE chirps DROP COLUMN short_id;
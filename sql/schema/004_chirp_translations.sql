@@ -0,0 +1,13 @@
+-- +goose Up
+CREATE TABLE chirp_translations (
+    chirp_id UUID NOT NULL REFERENCES chirps(id) ON DELETE CASCADE,
+    lang TEXT NOT NULL,
+    translated_body TEXT NOT NULL,
+    source_lang TEXT NOT NULL,
+    provider TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (chirp_id, lang)
+);
+
+-- +goose Down
+DROP TABLE chirp_translations;
@@ -0,0 +1,13 @@
+-- +goose Up
+CREATE TABLE chirp_templates (
+    id UUID PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    body TEXT NOT NULL,
+    CONSTRAINT chirp_templates_user_id_name_key UNIQUE (user_id, name)
+);
+
+-- +goose Down
+DROP TABLE chirp_templates;
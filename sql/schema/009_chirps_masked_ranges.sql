@@ -0,0 +1,5 @@
+-- +goose Up
+ALTER TABLE chirps ADD COLUMN masked_ranges JSONB NOT NULL DEFAULT '[]';
+
+-- +goose Down
+ALTER TABLE chirps DROP COLUMN masked_ranges;
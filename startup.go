@@ -0,0 +1,103 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/nuke87/go_http_server/internal/database"
+)
+
+// Wird beim Build per -ldflags "-X main.version=..." gesetzt.
+var version = "dev"
+
+// startupInfo beschreibt die effektive Konfiguration, gegen die beim Start geprüft wird.
+type startupInfo struct {
+	Version          string
+	Platform         string
+	ListenAddr       string
+	Storage          string
+	DBHost           string
+	MigrationVersion string
+	Features         []string
+	TLS              bool
+	AdminAuth        bool
+	AuthRoutes       bool
+	JWTSecretSet     bool
+	JWTProblems      []string // Warnungen aus auth.CheckSecret und dem Selbsttest
+	MissingIndexes   []database.Index
+}
+
+// Liefert Warnungen für gefährliche Kombinationen der Konfiguration.
+func startupWarnings(info startupInfo) []string {
+	var warnings []string
+	public := !isLocalhostAddr(info.ListenAddr)
+	if info.Platform == "dev" && public {
+		warnings = append(warnings, fmt.Sprintf("PLATFORM=dev while listening on non-localhost address %q", info.ListenAddr))
+	}
+	if !info.AdminAuth {
+		warnings = append(warnings, "admin endpoints are registered without authentication")
+	}
+	if info.AuthRoutes && !info.JWTSecretSet {
+		warnings = append(warnings, "auth routes are registered but no JWT secret is set")
+	}
+	warnings = append(warnings, info.JWTProblems...)
+	for _, idx := range info.MissingIndexes {
+		warnings = append(warnings, fmt.Sprintf("missing index %s for %s, create it with: %s", idx.Name, idx.Feature, idx.Create))
+	}
+	if !info.TLS && public {
+		warnings = append(warnings, fmt.Sprintf("TLS is off while listening on public address %q", info.ListenAddr))
+	}
+	return warnings
+}
+
+// Meldet, ob eine Listen-Adresse nur lokal erreichbar ist. ":8080" bindet an alle Interfaces.
+func isLocalhostAddr(addr string) bool {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		host = addr
+	}
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// Extrahiert Host und Port aus der Datenbank-URL, ohne Zugangsdaten.
+func dbHost(dbURL string) string {
+	u, err := url.Parse(strings.TrimSpace(dbURL))
+	if err != nil || u.Host == "" {
+		return "unknown"
+	}
+	return u.Host
+}
+
+// Liest die zuletzt angewendete goose-Migration, "unknown" wenn nicht möglich.
+func migrationVersion(dbConn *sql.DB) string {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	var v sql.NullInt64
+	err := dbConn.QueryRowContext(ctx, "SELECT MAX(version_id) FROM goose_db_version WHERE is_applied").Scan(&v)
+	if err != nil || !v.Valid {
+		return "unknown"
+	}
+	return fmt.Sprint(v.Int64)
+}
+
+func logStartupBanner(info startupInfo) {
+	slog.Info("starting chirpy",
+		"version", info.Version,
+		"platform", info.Platform,
+		"listen_addr", info.ListenAddr,
+		"storage", info.Storage,
+		"db_host", info.DBHost,
+		"migration_version", info.MigrationVersion,
+		"features", info.Features,
+	)
+}
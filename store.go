@@ -0,0 +1,97 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/nuke87/go_http_server/internal/database"
+)
+
+// Store ist die Datenhaltung, gegen die die Handler arbeiten. postgresStore ergänzt die
+// generierten *database.Queries um Transaktionen, memoryStore hält alles im Speicher (STORAGE=memory).
+// Nicht gefundene Zeilen melden beide mit sql.ErrNoRows, Unique-Verletzungen als
+// *pq.Error mit Code 23505 und Constraint-Namen (siehe isUniqueViolation).
+type Store interface {
+	// Users
+	CreateUser(ctx context.Context, arg database.CreateUserParams) (database.User, error)
+	GetUserByEmail(ctx context.Context, email string) (database.User, error)
+	GetUserByID(ctx context.Context, id uuid.UUID) (database.User, error)
+	GetUsers(ctx context.Context) ([]database.User, error)
+	UpgradeUserToChirpyRed(ctx context.Context, arg database.UpgradeUserToChirpyRedParams) (int64, error)
+	UpdateUserProfile(ctx context.Context, arg database.UpdateUserProfileParams) (database.User, error)
+	DeleteUser(ctx context.Context, id uuid.UUID) (int64, error)
+	DeleteAllUsers(ctx context.Context) (int64, error)
+
+	// Chirps
+	CreateChirp(ctx context.Context, arg database.CreateChirpParams) (database.Chirp, error)
+	GetChirpByID(ctx context.Context, id uuid.UUID) (database.Chirp, error)
+	GetChirpByShortID(ctx context.Context, shortID string) (database.Chirp, error)
+	// Listen gibt es je Richtung als eigene Query, damit Postgres den Index in Sortierrichtung nutzt.
+	GetChirpsAsc(ctx context.Context) ([]database.Chirp, error)
+	GetChirpsDesc(ctx context.Context) ([]database.Chirp, error)
+	GetChirpsByAuthorAsc(ctx context.Context, userID uuid.UUID) ([]database.Chirp, error)
+	GetChirpsByAuthorDesc(ctx context.Context, userID uuid.UUID) ([]database.Chirp, error)
+	GetChirpsPageAsc(ctx context.Context, arg database.GetChirpsPageAscParams) ([]database.Chirp, error)
+	GetChirpsPageDesc(ctx context.Context, arg database.GetChirpsPageDescParams) ([]database.Chirp, error)
+	GetChirpsLikedByAsc(ctx context.Context, arg database.GetChirpsLikedByAscParams) ([]database.Chirp, error)
+	GetChirpsLikedByDesc(ctx context.Context, arg database.GetChirpsLikedByDescParams) ([]database.Chirp, error)
+	UpdateChirp(ctx context.Context, arg database.UpdateChirpParams) (database.Chirp, error)
+	DeleteChirp(ctx context.Context, id uuid.UUID) error
+	DeleteChirpsByUser(ctx context.Context, userID uuid.UUID) (int64, error)
+	DeleteAllChirps(ctx context.Context) (int64, error)
+
+	// Follows
+	FollowUser(ctx context.Context, arg database.FollowUserParams) error
+	UnfollowUser(ctx context.Context, arg database.UnfollowUserParams) (int64, error)
+	GetFeedPage(ctx context.Context, arg database.GetFeedPageParams) ([]database.Chirp, error)
+
+	// Likes
+	LikeChirp(ctx context.Context, arg database.LikeChirpParams) error
+	UnlikeChirp(ctx context.Context, arg database.UnlikeChirpParams) (int64, error)
+	GetChirpLikeCounts(ctx context.Context, chirpIds []uuid.UUID) ([]database.GetChirpLikeCountsRow, error)
+	GetChirpsLikedByUser(ctx context.Context, arg database.GetChirpsLikedByUserParams) ([]uuid.UUID, error)
+
+	// Übersetzungen
+	CreateChirpTranslation(ctx context.Context, arg database.CreateChirpTranslationParams) error
+	GetChirpTranslation(ctx context.Context, arg database.GetChirpTranslationParams) (database.ChirpTranslation, error)
+	DeleteChirpTranslations(ctx context.Context, chirpID uuid.UUID) error
+
+	// Vorlagen
+	CreateChirpTemplate(ctx context.Context, arg database.CreateChirpTemplateParams) (database.ChirpTemplate, error)
+	GetChirpTemplate(ctx context.Context, arg database.GetChirpTemplateParams) (database.ChirpTemplate, error)
+	GetChirpTemplatesByUser(ctx context.Context, userID uuid.UUID) ([]database.ChirpTemplate, error)
+	CountChirpTemplatesByUser(ctx context.Context, userID uuid.UUID) (int64, error)
+	// Legt die Vorlage nur an, solange der User weniger als max hat (sonst errTemplateQuotaExceeded),
+	// atomar gegenüber parallelen Requests desselben Users. sql.ErrNoRows, wenn es den User nicht gibt.
+	CreateChirpTemplateWithinQuota(ctx context.Context, arg database.CreateChirpTemplateParams, max int64) (database.ChirpTemplate, error)
+	UpdateChirpTemplate(ctx context.Context, arg database.UpdateChirpTemplateParams) (database.ChirpTemplate, error)
+	DeleteChirpTemplate(ctx context.Context, arg database.DeleteChirpTemplateParams) (int64, error)
+
+	// Refresh-Tokens
+	CreateRefreshToken(ctx context.Context, arg database.CreateRefreshTokenParams) (database.RefreshToken, error)
+	GetUserFromRefreshToken(ctx context.Context, arg database.GetUserFromRefreshTokenParams) (database.User, error)
+	RevokeRefreshToken(ctx context.Context, arg database.RevokeRefreshTokenParams) error
+}
+
+var _ Store = (*postgresStore)(nil)
+
+// Der User hat bereits die erlaubte Anzahl Vorlagen.
+var errTemplateQuotaExceeded = errors.New("template quota exceeded")
+
+// Unterstützte Werte für STORAGE
+const (
+	storagePostgres = "postgres" // Standard
+	storageMemory   = "memory"   // Ohne Datenbank, Daten gehen beim Neustart verloren
+)
+
+func parseStorage(raw string) (string, error) {
+	switch raw {
+	case "", storagePostgres:
+		return storagePostgres, nil
+	case storageMemory:
+		return storageMemory, nil
+	}
+	return "", fmt.Errorf("STORAGE must be %q or %q, got %q", storagePostgres, storageMemory, raw)
+}
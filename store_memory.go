@@ -0,0 +1,721 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+
+	"github.com/nuke87/go_http_server/internal/database"
+)
+
+// memoryStore ist ein Store im Speicher, für Tests und Demos ohne Postgres. Er bildet
+// die Constraints und ON DELETE CASCADE des Schemas nach.
+type memoryStore struct {
+	mu            sync.Mutex
+	users         map[uuid.UUID]database.User
+	chirps        map[uuid.UUID]database.Chirp
+	likes         map[likeKey]database.ChirpLike
+	follows       map[followKey]database.Follow
+	translations  map[translationKey]database.ChirpTranslation
+	templates     map[uuid.UUID]database.ChirpTemplate
+	refreshTokens map[string]database.RefreshToken
+}
+
+type likeKey struct {
+	chirpID uuid.UUID
+	userID  uuid.UUID
+}
+
+type followKey struct {
+	followerID uuid.UUID
+	followeeID uuid.UUID
+}
+
+type translationKey struct {
+	chirpID uuid.UUID
+	lang    string
+}
+
+var _ Store = (*memoryStore)(nil)
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{
+		users:         map[uuid.UUID]database.User{},
+		chirps:        map[uuid.UUID]database.Chirp{},
+		likes:         map[likeKey]database.ChirpLike{},
+		follows:       map[followKey]database.Follow{},
+		translations:  map[translationKey]database.ChirpTranslation{},
+		templates:     map[uuid.UUID]database.ChirpTemplate{},
+		refreshTokens: map[string]database.RefreshToken{},
+	}
+}
+
+// Für /api/readyz: im Speicher ist die Datenhaltung immer erreichbar.
+func (s *memoryStore) PingContext(ctx context.Context) error {
+	return nil
+}
+
+// Fehler wie bei einer Postgres-Unique-Verletzung, damit isUniqueViolation greift.
+func uniqueViolation(constraint string) error {
+	return &pq.Error{Code: "23505", Constraint: constraint, Message: "duplicate key value violates unique constraint"}
+}
+
+// Users
+
+func (s *memoryStore) CreateUser(ctx context.Context, arg database.CreateUserParams) (database.User, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.users[arg.ID]; ok {
+		return database.User{}, uniqueViolation("users_pkey")
+	}
+	for _, u := range s.users {
+		if u.Email == arg.Email {
+			return database.User{}, uniqueViolation("users_email_key")
+		}
+	}
+	user := database.User{
+		ID:             arg.ID,
+		CreatedAt:      arg.CreatedAt,
+		UpdatedAt:      arg.UpdatedAt,
+		Email:          arg.Email,
+		HashedPassword: arg.HashedPassword,
+	}
+	s.users[user.ID] = user
+	return user, nil
+}
+
+func (s *memoryStore) GetUserByEmail(ctx context.Context, email string) (database.User, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, u := range s.users {
+		if u.Email == email {
+			return u, nil
+		}
+	}
+	return database.User{}, sql.ErrNoRows
+}
+
+func (s *memoryStore) GetUserByID(ctx context.Context, id uuid.UUID) (database.User, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	u, ok := s.users[id]
+	if !ok {
+		return database.User{}, sql.ErrNoRows
+	}
+	return u, nil
+}
+
+func (s *memoryStore) GetUsers(ctx context.Context) ([]database.User, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	users := make([]database.User, 0, len(s.users))
+	for _, u := range s.users {
+		users = append(users, u)
+	}
+	sort.Slice(users, func(i, j int) bool {
+		return lessCreated(users[i].CreatedAt, users[i].ID, users[j].CreatedAt, users[j].ID)
+	})
+	return users, nil
+}
+
+func (s *memoryStore) UpgradeUserToChirpyRed(ctx context.Context, arg database.UpgradeUserToChirpyRedParams) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	u, ok := s.users[arg.ID]
+	if !ok {
+		return 0, nil
+	}
+	u.IsChirpyRed = true
+	u.UpdatedAt = arg.UpdatedAt
+	s.users[u.ID] = u
+	return 1, nil
+}
+
+func (s *memoryStore) UpdateUserProfile(ctx context.Context, arg database.UpdateUserProfileParams) (database.User, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	u, ok := s.users[arg.ID]
+	if !ok {
+		return database.User{}, sql.ErrNoRows
+	}
+	u.DisplayName = arg.DisplayName
+	u.Bio = arg.Bio
+	u.UpdatedAt = arg.UpdatedAt
+	s.users[u.ID] = u
+	return u, nil
+}
+
+func (s *memoryStore) DeleteUser(ctx context.Context, id uuid.UUID) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.users[id]; !ok {
+		return 0, nil
+	}
+	s.deleteUserLocked(id)
+	return 1, nil
+}
+
+func (s *memoryStore) DeleteAllUsers(ctx context.Context) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	n := int64(len(s.users))
+	for id := range s.users {
+		s.deleteUserLocked(id)
+	}
+	return n, nil
+}
+
+// Löscht einen User samt allem, was per ON DELETE CASCADE an ihm hängt.
+func (s *memoryStore) deleteUserLocked(id uuid.UUID) {
+	delete(s.users, id)
+	for chirpID, c := range s.chirps {
+		if c.UserID == id {
+			s.deleteChirpLocked(chirpID)
+		}
+	}
+	for templateID, t := range s.templates {
+		if t.UserID == id {
+			delete(s.templates, templateID)
+		}
+	}
+	for token, rt := range s.refreshTokens {
+		if rt.UserID == id {
+			delete(s.refreshTokens, token)
+		}
+	}
+	for key := range s.likes {
+		if key.userID == id {
+			delete(s.likes, key)
+		}
+	}
+	for key := range s.follows {
+		if key.followerID == id || key.followeeID == id {
+			delete(s.follows, key)
+		}
+	}
+}
+
+// Chirps
+
+func (s *memoryStore) CreateChirp(ctx context.Context, arg database.CreateChirpParams) (database.Chirp, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.users[arg.UserID]; !ok {
+		return database.Chirp{}, &pq.Error{Code: "23503", Constraint: "chirps_user_id_fkey", Message: "violates foreign key constraint"}
+	}
+	if _, ok := s.chirps[arg.ID]; ok {
+		return database.Chirp{}, uniqueViolation("chirps_pkey")
+	}
+	for _, c := range s.chirps {
+		if c.ShortID == arg.ShortID {
+			return database.Chirp{}, uniqueViolation("chirps_short_id_idx")
+		}
+	}
+	maskedRanges := arg.MaskedRanges
+	if len(maskedRanges) == 0 {
+		maskedRanges = []byte("[]") // DEFAULT der Spalte
+	}
+	chirp := database.Chirp{
+		ID:           arg.ID,
+		CreatedAt:    arg.CreatedAt,
+		UpdatedAt:    arg.UpdatedAt,
+		Body:         arg.Body,
+		UserID:       arg.UserID,
+		ShortID:      arg.ShortID,
+		MaskedRanges: maskedRanges,
+	}
+	s.chirps[chirp.ID] = chirp
+	return chirp, nil
+}
+
+func (s *memoryStore) GetChirpByID(ctx context.Context, id uuid.UUID) (database.Chirp, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	c, ok := s.chirps[id]
+	if !ok {
+		return database.Chirp{}, sql.ErrNoRows
+	}
+	return c, nil
+}
+
+func (s *memoryStore) GetChirpByShortID(ctx context.Context, shortID string) (database.Chirp, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, c := range s.chirps {
+		if c.ShortID == shortID {
+			return c, nil
+		}
+	}
+	return database.Chirp{}, sql.ErrNoRows
+}
+
+func (s *memoryStore) GetChirpsAsc(ctx context.Context) ([]database.Chirp, error) {
+	return s.listChirps(func(database.Chirp) bool { return true }, "asc"), nil
+}
+
+func (s *memoryStore) GetChirpsDesc(ctx context.Context) ([]database.Chirp, error) {
+	return s.listChirps(func(database.Chirp) bool { return true }, "desc"), nil
+}
+
+func (s *memoryStore) GetChirpsByAuthorAsc(ctx context.Context, userID uuid.UUID) ([]database.Chirp, error) {
+	return s.listChirps(func(c database.Chirp) bool { return c.UserID == userID }, "asc"), nil
+}
+
+func (s *memoryStore) GetChirpsByAuthorDesc(ctx context.Context, userID uuid.UUID) ([]database.Chirp, error) {
+	return s.listChirps(func(c database.Chirp) bool { return c.UserID == userID }, "desc"), nil
+}
+
+func (s *memoryStore) GetChirpsPageAsc(ctx context.Context, arg database.GetChirpsPageAscParams) ([]database.Chirp, error) {
+	return s.chirpsPage(arg, "asc"), nil
+}
+
+func (s *memoryStore) GetChirpsPageDesc(ctx context.Context, arg database.GetChirpsPageDescParams) ([]database.Chirp, error) {
+	return s.chirpsPage(database.GetChirpsPageAscParams(arg), "desc"), nil
+}
+
+// Gemeinsame Umsetzung von GetChirpsPageAsc und GetChirpsPageDesc (gleiche Parameter).
+func (s *memoryStore) chirpsPage(arg database.GetChirpsPageAscParams, order string) []database.Chirp {
+	chirps := s.listChirps(func(c database.Chirp) bool {
+		if arg.AuthorID.Valid && c.UserID != arg.AuthorID.UUID {
+			return false
+		}
+		if arg.LikedBy.Valid && !s.likedLocked(c.ID, arg.LikedBy.UUID) {
+			return false
+		}
+		// Keyset wie in SQL: (created_at, id) < bzw. > Cursor
+		if order == "desc" {
+			return lessCreated(c.CreatedAt, c.ID, arg.CursorCreatedAt, arg.CursorID)
+		}
+		return lessCreated(arg.CursorCreatedAt, arg.CursorID, c.CreatedAt, c.ID)
+	}, order)
+	if int(arg.PageLimit) < len(chirps) {
+		chirps = chirps[:arg.PageLimit]
+	}
+	return chirps
+}
+
+func (s *memoryStore) GetChirpsLikedByAsc(ctx context.Context, arg database.GetChirpsLikedByAscParams) ([]database.Chirp, error) {
+	return s.chirpsLikedBy(arg, "asc"), nil
+}
+
+func (s *memoryStore) GetChirpsLikedByDesc(ctx context.Context, arg database.GetChirpsLikedByDescParams) ([]database.Chirp, error) {
+	return s.chirpsLikedBy(database.GetChirpsLikedByAscParams(arg), "desc"), nil
+}
+
+func (s *memoryStore) chirpsLikedBy(arg database.GetChirpsLikedByAscParams, order string) []database.Chirp {
+	return s.listChirps(func(c database.Chirp) bool {
+		if arg.AuthorID.Valid && c.UserID != arg.AuthorID.UUID {
+			return false
+		}
+		return s.likedLocked(c.ID, arg.LikedBy)
+	}, order)
+}
+
+// Chirps, die keep erfüllen, nach (created_at, id) sortiert; "desc" absteigend. keep läuft unter s.mu.
+func (s *memoryStore) listChirps(keep func(database.Chirp) bool, order string) []database.Chirp {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	chirps := []database.Chirp{}
+	for _, c := range s.chirps {
+		if keep(c) {
+			chirps = append(chirps, c)
+		}
+	}
+	sort.Slice(chirps, func(i, j int) bool {
+		if order == "desc" {
+			i, j = j, i
+		}
+		return lessCreated(chirps[i].CreatedAt, chirps[i].ID, chirps[j].CreatedAt, chirps[j].ID)
+	})
+	return chirps
+}
+
+func (s *memoryStore) UpdateChirp(ctx context.Context, arg database.UpdateChirpParams) (database.Chirp, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	c, ok := s.chirps[arg.ID]
+	if !ok {
+		return database.Chirp{}, sql.ErrNoRows
+	}
+	c.Body = arg.Body
+	c.MaskedRanges = arg.MaskedRanges
+	c.UpdatedAt = arg.UpdatedAt
+	s.chirps[c.ID] = c
+	return c, nil
+}
+
+func (s *memoryStore) DeleteChirp(ctx context.Context, id uuid.UUID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.deleteChirpLocked(id)
+	return nil
+}
+
+func (s *memoryStore) DeleteChirpsByUser(ctx context.Context, userID uuid.UUID) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var n int64
+	for id, c := range s.chirps {
+		if c.UserID == userID {
+			s.deleteChirpLocked(id)
+			n++
+		}
+	}
+	return n, nil
+}
+
+func (s *memoryStore) DeleteAllChirps(ctx context.Context) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	n := int64(len(s.chirps))
+	for id := range s.chirps {
+		s.deleteChirpLocked(id)
+	}
+	return n, nil
+}
+
+// Löscht einen Chirp samt seiner Übersetzungen und Likes (ON DELETE CASCADE).
+func (s *memoryStore) deleteChirpLocked(id uuid.UUID) {
+	delete(s.chirps, id)
+	for key := range s.translations {
+		if key.chirpID == id {
+			delete(s.translations, key)
+		}
+	}
+	for key := range s.likes {
+		if key.chirpID == id {
+			delete(s.likes, key)
+		}
+	}
+}
+
+// Follows
+
+func (s *memoryStore) FollowUser(ctx context.Context, arg database.FollowUserParams) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if arg.FollowerID == arg.FolloweeID {
+		return &pq.Error{Code: "23514", Constraint: "follows_no_self_follow", Message: "violates check constraint"}
+	}
+	if _, ok := s.users[arg.FollowerID]; !ok {
+		return &pq.Error{Code: "23503", Constraint: "follows_follower_id_fkey", Message: "violates foreign key constraint"}
+	}
+	if _, ok := s.users[arg.FolloweeID]; !ok {
+		return &pq.Error{Code: "23503", Constraint: "follows_followee_id_fkey", Message: "violates foreign key constraint"}
+	}
+	key := followKey{followerID: arg.FollowerID, followeeID: arg.FolloweeID}
+	if _, ok := s.follows[key]; ok {
+		return nil // ON CONFLICT DO NOTHING
+	}
+	s.follows[key] = database.Follow{FollowerID: arg.FollowerID, FolloweeID: arg.FolloweeID, CreatedAt: arg.CreatedAt}
+	return nil
+}
+
+func (s *memoryStore) UnfollowUser(ctx context.Context, arg database.UnfollowUserParams) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key := followKey{followerID: arg.FollowerID, followeeID: arg.FolloweeID}
+	if _, ok := s.follows[key]; !ok {
+		return 0, nil
+	}
+	delete(s.follows, key)
+	return 1, nil
+}
+
+func (s *memoryStore) GetFeedPage(ctx context.Context, arg database.GetFeedPageParams) ([]database.Chirp, error) {
+	chirps := s.listChirps(func(c database.Chirp) bool {
+		if _, ok := s.follows[followKey{followerID: arg.FollowerID, followeeID: c.UserID}]; !ok {
+			return false
+		}
+		return lessCreated(c.CreatedAt, c.ID, arg.CursorCreatedAt, arg.CursorID)
+	}, "desc")
+	if int(arg.PageLimit) < len(chirps) {
+		chirps = chirps[:arg.PageLimit]
+	}
+	return chirps, nil
+}
+
+// Likes
+
+func (s *memoryStore) LikeChirp(ctx context.Context, arg database.LikeChirpParams) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.chirps[arg.ChirpID]; !ok {
+		return &pq.Error{Code: "23503", Constraint: "chirp_likes_chirp_id_fkey", Message: "violates foreign key constraint"}
+	}
+	if _, ok := s.users[arg.UserID]; !ok {
+		return &pq.Error{Code: "23503", Constraint: "chirp_likes_user_id_fkey", Message: "violates foreign key constraint"}
+	}
+	key := likeKey{chirpID: arg.ChirpID, userID: arg.UserID}
+	if _, ok := s.likes[key]; ok {
+		return nil // ON CONFLICT DO NOTHING
+	}
+	s.likes[key] = database.ChirpLike{ChirpID: arg.ChirpID, UserID: arg.UserID, CreatedAt: arg.CreatedAt}
+	return nil
+}
+
+func (s *memoryStore) UnlikeChirp(ctx context.Context, arg database.UnlikeChirpParams) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key := likeKey{chirpID: arg.ChirpID, userID: arg.UserID}
+	if _, ok := s.likes[key]; !ok {
+		return 0, nil
+	}
+	delete(s.likes, key)
+	return 1, nil
+}
+
+func (s *memoryStore) GetChirpLikeCounts(ctx context.Context, chirpIds []uuid.UUID) ([]database.GetChirpLikeCountsRow, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	wanted := map[uuid.UUID]bool{}
+	for _, id := range chirpIds {
+		wanted[id] = true
+	}
+	counts := map[uuid.UUID]int64{}
+	for key := range s.likes {
+		if wanted[key.chirpID] {
+			counts[key.chirpID]++
+		}
+	}
+	rows := make([]database.GetChirpLikeCountsRow, 0, len(counts))
+	for id, n := range counts {
+		rows = append(rows, database.GetChirpLikeCountsRow{ChirpID: id, LikeCount: n})
+	}
+	return rows, nil
+}
+
+func (s *memoryStore) GetChirpsLikedByUser(ctx context.Context, arg database.GetChirpsLikedByUserParams) ([]uuid.UUID, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var liked []uuid.UUID
+	for _, id := range arg.ChirpIds {
+		if s.likedLocked(id, arg.UserID) {
+			liked = append(liked, id)
+		}
+	}
+	return liked, nil
+}
+
+func (s *memoryStore) likedLocked(chirpID, userID uuid.UUID) bool {
+	_, ok := s.likes[likeKey{chirpID: chirpID, userID: userID}]
+	return ok
+}
+
+// Übersetzungen
+
+func (s *memoryStore) CreateChirpTranslation(ctx context.Context, arg database.CreateChirpTranslationParams) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key := translationKey{chirpID: arg.ChirpID, lang: arg.Lang}
+	if _, ok := s.translations[key]; ok {
+		return nil // ON CONFLICT DO NOTHING
+	}
+	s.translations[key] = database.ChirpTranslation{
+		ChirpID:        arg.ChirpID,
+		Lang:           arg.Lang,
+		TranslatedBody: arg.TranslatedBody,
+		SourceLang:     arg.SourceLang,
+		Provider:       arg.Provider,
+		CreatedAt:      time.Now().UTC(),
+	}
+	return nil
+}
+
+func (s *memoryStore) GetChirpTranslation(ctx context.Context, arg database.GetChirpTranslationParams) (database.ChirpTranslation, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t, ok := s.translations[translationKey{chirpID: arg.ChirpID, lang: arg.Lang}]
+	if !ok {
+		return database.ChirpTranslation{}, sql.ErrNoRows
+	}
+	return t, nil
+}
+
+func (s *memoryStore) DeleteChirpTranslations(ctx context.Context, chirpID uuid.UUID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for key := range s.translations {
+		if key.chirpID == chirpID {
+			delete(s.translations, key)
+		}
+	}
+	return nil
+}
+
+// Vorlagen
+
+func (s *memoryStore) CreateChirpTemplate(ctx context.Context, arg database.CreateChirpTemplateParams) (database.ChirpTemplate, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.createChirpTemplateLocked(arg)
+}
+
+func (s *memoryStore) CreateChirpTemplateWithinQuota(ctx context.Context, arg database.CreateChirpTemplateParams, max int64) (database.ChirpTemplate, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.users[arg.UserID]; !ok {
+		return database.ChirpTemplate{}, sql.ErrNoRows
+	}
+	if s.countChirpTemplatesLocked(arg.UserID) >= max {
+		return database.ChirpTemplate{}, errTemplateQuotaExceeded
+	}
+	return s.createChirpTemplateLocked(arg)
+}
+
+func (s *memoryStore) createChirpTemplateLocked(arg database.CreateChirpTemplateParams) (database.ChirpTemplate, error) {
+	if s.templateNameTakenLocked(arg.UserID, arg.Name, uuid.Nil) {
+		return database.ChirpTemplate{}, uniqueViolation("chirp_templates_user_id_name_key")
+	}
+	template := database.ChirpTemplate{
+		ID:        arg.ID,
+		CreatedAt: arg.CreatedAt,
+		UpdatedAt: arg.UpdatedAt,
+		UserID:    arg.UserID,
+		Name:      arg.Name,
+		Body:      arg.Body,
+	}
+	s.templates[template.ID] = template
+	return template, nil
+}
+
+func (s *memoryStore) GetChirpTemplate(ctx context.Context, arg database.GetChirpTemplateParams) (database.ChirpTemplate, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t, ok := s.templates[arg.ID]
+	if !ok || t.UserID != arg.UserID {
+		return database.ChirpTemplate{}, sql.ErrNoRows
+	}
+	return t, nil
+}
+
+func (s *memoryStore) GetChirpTemplatesByUser(ctx context.Context, userID uuid.UUID) ([]database.ChirpTemplate, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	templates := []database.ChirpTemplate{}
+	for _, t := range s.templates {
+		if t.UserID == userID {
+			templates = append(templates, t)
+		}
+	}
+	sort.Slice(templates, func(i, j int) bool {
+		return lessCreated(templates[i].CreatedAt, templates[i].ID, templates[j].CreatedAt, templates[j].ID)
+	})
+	return templates, nil
+}
+
+func (s *memoryStore) CountChirpTemplatesByUser(ctx context.Context, userID uuid.UUID) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.countChirpTemplatesLocked(userID), nil
+}
+
+func (s *memoryStore) countChirpTemplatesLocked(userID uuid.UUID) int64 {
+	var n int64
+	for _, t := range s.templates {
+		if t.UserID == userID {
+			n++
+		}
+	}
+	return n
+}
+
+func (s *memoryStore) UpdateChirpTemplate(ctx context.Context, arg database.UpdateChirpTemplateParams) (database.ChirpTemplate, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t, ok := s.templates[arg.ID]
+	if !ok || t.UserID != arg.UserID {
+		return database.ChirpTemplate{}, sql.ErrNoRows
+	}
+	if s.templateNameTakenLocked(arg.UserID, arg.Name, arg.ID) {
+		return database.ChirpTemplate{}, uniqueViolation("chirp_templates_user_id_name_key")
+	}
+	t.Name = arg.Name
+	t.Body = arg.Body
+	t.UpdatedAt = arg.UpdatedAt
+	s.templates[t.ID] = t
+	return t, nil
+}
+
+func (s *memoryStore) DeleteChirpTemplate(ctx context.Context, arg database.DeleteChirpTemplateParams) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t, ok := s.templates[arg.ID]
+	if !ok || t.UserID != arg.UserID {
+		return 0, nil
+	}
+	delete(s.templates, arg.ID)
+	return 1, nil
+}
+
+// Meldet, ob der User schon eine andere Vorlage (ID != except) mit diesem Namen hat.
+func (s *memoryStore) templateNameTakenLocked(userID uuid.UUID, name string, except uuid.UUID) bool {
+	for _, t := range s.templates {
+		if t.UserID == userID && t.Name == name && t.ID != except {
+			return true
+		}
+	}
+	return false
+}
+
+// Refresh-Tokens
+
+func (s *memoryStore) CreateRefreshToken(ctx context.Context, arg database.CreateRefreshTokenParams) (database.RefreshToken, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.refreshTokens[arg.Token]; ok {
+		return database.RefreshToken{}, uniqueViolation("refresh_tokens_pkey")
+	}
+	rt := database.RefreshToken{
+		Token:     arg.Token,
+		CreatedAt: arg.CreatedAt,
+		UpdatedAt: arg.CreatedAt,
+		UserID:    arg.UserID,
+		ExpiresAt: arg.ExpiresAt,
+	}
+	s.refreshTokens[rt.Token] = rt
+	return rt, nil
+}
+
+func (s *memoryStore) GetUserFromRefreshToken(ctx context.Context, arg database.GetUserFromRefreshTokenParams) (database.User, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	rt, ok := s.refreshTokens[arg.Token]
+	if !ok || rt.RevokedAt.Valid || !rt.ExpiresAt.After(arg.ExpiresAt) {
+		return database.User{}, sql.ErrNoRows
+	}
+	u, ok := s.users[rt.UserID]
+	if !ok {
+		return database.User{}, sql.ErrNoRows
+	}
+	return u, nil
+}
+
+func (s *memoryStore) RevokeRefreshToken(ctx context.Context, arg database.RevokeRefreshTokenParams) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	rt, ok := s.refreshTokens[arg.Token]
+	if !ok {
+		return nil
+	}
+	rt.RevokedAt = arg.RevokedAt
+	if arg.RevokedAt.Valid {
+		rt.UpdatedAt = arg.RevokedAt.Time
+	}
+	s.refreshTokens[rt.Token] = rt
+	return nil
+}
+
+// Reihenfolge wie ORDER BY created_at, id in Postgres (UUIDs byteweise verglichen).
+func lessCreated(aTime time.Time, aID uuid.UUID, bTime time.Time, bID uuid.UUID) bool {
+	if !aTime.Equal(bTime) {
+		return aTime.Before(bTime)
+	}
+	return bytes.Compare(aID[:], bID[:]) < 0
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/nuke87/go_http_server/internal/database"
+)
+
+// postgresStore ist der Store für STORAGE=postgres: die generierten Queries plus die
+// Verbindung für Abläufe, die mehrere Queries in einer Transaktion brauchen.
+type postgresStore struct {
+	*database.Queries
+	db *sql.DB
+}
+
+func newPostgresStore(db *sql.DB) *postgresStore {
+	return &postgresStore{Queries: database.New(db), db: db}
+}
+
+// Sperrt die Zeile des Users, zählt und legt erst dann an. Parallele Requests desselben
+// Users warten auf die Sperre und sehen danach die neue Anzahl (READ COMMITTED liest pro Query neu).
+func (s *postgresStore) CreateChirpTemplateWithinQuota(ctx context.Context, arg database.CreateChirpTemplateParams, max int64) (database.ChirpTemplate, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return database.ChirpTemplate{}, err
+	}
+	defer tx.Rollback()
+	qtx := s.WithTx(tx)
+
+	if _, err := qtx.LockUserForUpdate(ctx, arg.UserID); err != nil {
+		return database.ChirpTemplate{}, err
+	}
+	count, err := qtx.CountChirpTemplatesByUser(ctx, arg.UserID)
+	if err != nil {
+		return database.ChirpTemplate{}, err
+	}
+	if count >= max {
+		return database.ChirpTemplate{}, errTemplateQuotaExceeded
+	}
+	template, err := qtx.CreateChirpTemplate(ctx, arg)
+	if err != nil {
+		return database.ChirpTemplate{}, err
+	}
+	return template, tx.Commit()
+}
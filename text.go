@@ -0,0 +1,85 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// Fehlercodes der Textprüfung
+const (
+	errCodeTooShort = "too_short"
+	errCodeTooLong  = "too_long"
+)
+
+// textPolicy legt fest, welche Steuerzeichen ein Text enthalten darf.
+type textPolicy struct {
+	Multiline bool   // \n und \t erlaubt; \r\n wird zu \n, mehr als zwei Zeilenumbrüche am Stück werden gekürzt
+	Bidi      string // Umgang mit Bidi-Steuerzeichen: bidiStrip oder bidiReject
+}
+
+// Text ist vom Client gelieferter Freitext (Chirps, Vorlagen, ...).
+type Text string
+
+// Validate prüft und normalisiert den Text in dieser Reihenfolge:
+//   - ungültiges UTF-8 wird abgelehnt,
+//   - Steuerzeichen werden nach policy abgelehnt bzw. Bidi-Steuerzeichen entfernt,
+//   - der Text wird nach NFC normalisiert, damit gleichwertige Strings gleich verglichen werden;
+//     erst nach dem Entfernen, sonst bliebe z.B. "e" LRM U+0301 unnormalisiert zurück,
+//   - die Länge in Runes muss zwischen min und max liegen (max <= 0: keine Obergrenze).
+//
+// Die Längenprüfung gilt für den bereinigten Text. Fehler tragen noch kein Feld, siehe forField.
+func (t Text) Validate(min, max int, policy textPolicy) (Text, *requestError) {
+	s := string(t)
+	if !utf8.ValidString(s) {
+		return "", &requestError{status: http.StatusBadRequest, code: errCodeInvalidCharacters, msg: "is not valid UTF-8"}
+	}
+	if policy.Multiline {
+		s = strings.ReplaceAll(s, "\r\n", "\n")
+	}
+
+	var b strings.Builder
+	b.Grow(len(s))
+	newlines := 0
+	for _, r := range s {
+		switch {
+		case r == '\n' && policy.Multiline:
+			newlines++
+			if newlines > 2 {
+				continue
+			}
+		case r == '\t' && policy.Multiline:
+			newlines = 0
+		case r < 0x20 || r == 0x7F:
+			return "", &requestError{status: http.StatusBadRequest, code: errCodeInvalidCharacters, msg: "contains control characters"}
+		case isBidiControl(r):
+			if policy.Bidi == bidiReject {
+				return "", &requestError{status: http.StatusBadRequest, code: errCodeInvalidCharacters, msg: "contains bidirectional control characters"}
+			}
+			continue
+		default:
+			newlines = 0
+		}
+		b.WriteRune(r)
+	}
+	s = norm.NFC.String(b.String())
+
+	length := utf8.RuneCountInString(s)
+	if length < min {
+		return "", &requestError{status: http.StatusBadRequest, code: errCodeTooShort, msg: fmt.Sprintf("must be at least %d characters", min)}
+	}
+	if max > 0 && length > max {
+		return "", &requestError{status: http.StatusBadRequest, code: errCodeTooLong, msg: fmt.Sprintf("must be at most %d characters", max)}
+	}
+	return Text(s), nil
+}
+
+// Ordnet einen Fehler aus Validate einem Feld des Requests zu.
+func (e *requestError) forField(field string) *requestError {
+	e.field = field
+	e.msg = field + " " + e.msg
+	return e
+}
@@ -0,0 +1,195 @@
+package main
+
+import (
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/quick"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+func TestTextValidateCharacterClasses(t *testing.T) {
+	multiline := textPolicy{Multiline: true, Bidi: bidiStrip}
+	singleLine := textPolicy{Bidi: bidiStrip}
+	rejectBidi := textPolicy{Multiline: true, Bidi: bidiReject}
+
+	tests := []struct {
+		name     string
+		in       string
+		policy   textPolicy
+		want     string // erwarteter Text, wenn wantCode leer ist
+		wantCode string
+	}{
+		// C0-Steuerzeichen und DEL
+		{"NUL", "a\x00b", multiline, "", errCodeInvalidCharacters},
+		{"SOH", "a\x01b", multiline, "", errCodeInvalidCharacters},
+		{"BEL", "a\ab", multiline, "", errCodeInvalidCharacters},
+		{"backspace", "a\bb", multiline, "", errCodeInvalidCharacters},
+		{"escape", "a\x1b[31mred", multiline, "", errCodeInvalidCharacters},
+		{"unit separator", "a\x1fb", multiline, "", errCodeInvalidCharacters},
+		{"DEL", "a\x7fb", multiline, "", errCodeInvalidCharacters},
+		{"lone carriage return", "a\rb", multiline, "", errCodeInvalidCharacters},
+		{"vertical tab", "a\vb", multiline, "", errCodeInvalidCharacters},
+		{"form feed", "a\fb", multiline, "", errCodeInvalidCharacters},
+
+		// Zeilenumbruch und Tab
+		{"newline multiline", "a\nb", multiline, "a\nb", ""},
+		{"tab multiline", "a\tb", multiline, "a\tb", ""},
+		{"CRLF becomes LF", "a\r\nb", multiline, "a\nb", ""},
+		{"newline single line", "a\nb", singleLine, "", errCodeInvalidCharacters},
+		{"tab single line", "a\tb", singleLine, "", errCodeInvalidCharacters},
+		{"two newlines kept", "a\n\nb", multiline, "a\n\nb", ""},
+		{"more than two newlines collapsed", "a\n\n\n\n\nb", multiline, "a\n\nb", ""},
+		{"CRLF runs collapsed", "a\r\n\r\n\r\nb", multiline, "a\n\nb", ""},
+		{"tab resets the newline run", "a\n\n\t\nb", multiline, "a\n\n\t\nb", ""},
+
+		// Bidi-Steuerzeichen: entfernen oder ablehnen
+		{"LRE stripped", "a\u202ab", multiline, "ab", ""},
+		{"RLO stripped", "a\u202eb", multiline, "ab", ""},
+		{"PDF stripped", "a\u202cb", multiline, "ab", ""},
+		{"LRI stripped", "a\u2066b", multiline, "ab", ""},
+		{"PDI stripped", "a\u2069b", multiline, "ab", ""},
+		{"LRM stripped", "a\u200eb", multiline, "ab", ""},
+		{"RLM stripped", "a\u200fb", multiline, "ab", ""},
+		{"ALM stripped", "a\u061cb", multiline, "ab", ""},
+		{"RLO rejected", "a\u202eb", rejectBidi, "", errCodeInvalidCharacters},
+		{"FSI rejected", "a\u2068b", rejectBidi, "", errCodeInvalidCharacters},
+		{"RLM rejected", "a\u200fb", rejectBidi, "", errCodeInvalidCharacters},
+
+		// Zero-Width-Zeichen bleiben, sie bilden Emoji-Sequenzen und Schriften wie Devanagari
+		{"zero width joiner", "👩\u200d💻", multiline, "👩\u200d💻", ""},
+		{"zero width non-joiner", "a\u200cb", multiline, "a\u200cb", ""},
+
+		// Kodierung
+		{"invalid UTF-8", "a\xffb", multiline, "", errCodeInvalidCharacters},
+		{"truncated UTF-8", "a\xe2\x82", multiline, "", errCodeInvalidCharacters},
+		{"NFC normalization", "e\u0301", multiline, "\u00e9", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, reqErr := Text(tt.in).Validate(0, 0, tt.policy)
+			if tt.wantCode != "" {
+				if reqErr == nil {
+					t.Fatalf("accepted as %q", got)
+				}
+				if reqErr.code != tt.wantCode {
+					t.Errorf("code = %q, want %q", reqErr.code, tt.wantCode)
+				}
+				return
+			}
+			if reqErr != nil {
+				t.Fatalf("rejected: %s", reqErr.msg)
+			}
+			if string(got) != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// Das Längenlimit gilt für den bereinigten Text.
+func TestTextValidateLengthAfterSanitation(t *testing.T) {
+	policy := textPolicy{Multiline: true, Bidi: bidiStrip}
+	tests := []struct {
+		name     string
+		in       string
+		wantCode string
+	}{
+		{"bidi marks do not count", strings.Repeat("a", 10) + strings.Repeat("\u200e", 5), ""},
+		{"collapsed newlines do not count", strings.Repeat("a", 8) + "\n\n\n\n\n", ""},
+		{"over limit after sanitation", strings.Repeat("a", 9) + "\n\n\n", errCodeTooLong},
+		{"only bidi marks is too short", "\u200e\u200f", errCodeTooShort},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, reqErr := Text(tt.in).Validate(1, 10, policy)
+			switch {
+			case tt.wantCode == "" && reqErr != nil:
+				t.Errorf("rejected: %s", reqErr.msg)
+			case tt.wantCode != "" && (reqErr == nil || reqErr.code != tt.wantCode):
+				t.Errorf("err = %v, want code %q", reqErr, tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestRequestErrorForField(t *testing.T) {
+	_, reqErr := Text("a\x00").Validate(0, 0, textPolicy{})
+	reqErr = reqErr.forField("display_name")
+	if reqErr.field != "display_name" || reqErr.msg != "display_name contains control characters" {
+		t.Errorf("field/msg = %q/%q", reqErr.field, reqErr.msg)
+	}
+}
+
+// Bausteine für zufällige Texte: Buchstaben, kombinierende Zeichen, Hangul-Jamo,
+// Bidi-Steuerzeichen, Zeilenumbrüche und Zeichen außerhalb der BMP.
+var textSampleRunes = []rune{
+	'a', 'e', 'o', 'A', ' ', '.', '\n', '\r', '\t',
+	'\u0301', '\u0308', '\u0323', '\u0327', '\u0653', '\u093c',
+	'\u1100', '\u1161', '\u11a8', '\u00e9', '\u212b', '\u0627',
+	'\u200e', '\u200f', '\u202a', '\u202e', '\u2066', '\u2069', '\u061c', '\u200d',
+	'\U0001F469', '\U0001F4BB', '\U0001D11E', '\U0001F3FD', '\U00020000',
+}
+
+// textSample erzeugt für testing/quick Texte aus textSampleRunes.
+type textSample string
+
+func (textSample) Generate(r *rand.Rand, size int) reflect.Value {
+	runes := make([]rune, r.Intn(size+1))
+	for i := range runes {
+		runes[i] = textSampleRunes[r.Intn(len(textSampleRunes))]
+	}
+	return reflect.ValueOf(textSample(runes))
+}
+
+// Eigenschaften von Validate für beliebige Eingaben: das Ergebnis ist NFC, eine zweite
+// Prüfung ändert nichts, und das Längenlimit zählt die Runes des Ergebnisses.
+func TestTextValidateProperties(t *testing.T) {
+	policies := []textPolicy{
+		{Multiline: true, Bidi: bidiStrip},
+		{Bidi: bidiStrip},
+		{Multiline: true, Bidi: bidiReject},
+	}
+	for _, policy := range policies {
+		property := func(in textSample) bool {
+			got, reqErr := Text(in).Validate(0, 0, policy)
+			if reqErr != nil {
+				return reqErr.code == errCodeInvalidCharacters
+			}
+			if !norm.NFC.IsNormalString(string(got)) {
+				t.Logf("%+q -> %+q is not NFC", in, got)
+				return false
+			}
+			again, reqErr := got.Validate(0, 0, policy)
+			if reqErr != nil || again != got {
+				t.Logf("%+q -> %+q, validated again -> %+q (%v)", in, got, again, reqErr)
+				return false
+			}
+			n := utf8.RuneCountInString(string(got))
+			if _, reqErr := Text(in).Validate(n, n, policy); reqErr != nil {
+				t.Logf("%+q -> %+q: rejected with min = max = %d runes: %s", in, got, n, reqErr.msg)
+				return false
+			}
+			if n > 1 { // max <= 0 hieße: keine Obergrenze
+				if _, reqErr := Text(in).Validate(0, n-1, policy); reqErr == nil || reqErr.code != errCodeTooLong {
+					t.Logf("%+q -> %+q: accepted with max %d", in, got, n-1)
+					return false
+				}
+			}
+			_, reqErr = Text(in).Validate(n+1, 0, policy)
+			return reqErr != nil && reqErr.code == errCodeTooShort
+		}
+		if err := quick.Check(property, &quick.Config{MaxCount: 2000}); err != nil {
+			t.Errorf("policy %+v: %v", policy, err)
+		}
+	}
+
+	// Ein entferntes Bidi-Zeichen zwischen Buchstabe und Akzent darf keinen unnormalisierten Text hinterlassen
+	got, reqErr := Text("e\u200e\u0301").Validate(0, 0, policies[0])
+	if reqErr != nil || got != "\u00e9" {
+		t.Errorf("got %+q, %v; want %+q", got, reqErr, "\u00e9")
+	}
+}
@@ -0,0 +1,25 @@
+package main
+
+import (
+	"unicode"
+
+	"golang.org/x/text/cases"
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// foldForMatch bringt ein Wort in eine Vergleichsform: Unicode-Case-Folding
+// ("SHARBERT" == "sharbert", "Straße" == "STRASSE") und, falls aktiviert, Entfernen
+// von diakritischen Zeichen über NFD + Löschen der Kombinationszeichen
+// ("Schärbert" == "scharbert"). Das türkische punktlose ı ist ein eigener Buchstabe
+// und wird nicht zu i; İ wird beim Entfernen der Diakritika dagegen zu i.
+func foldForMatch(s string, stripDiacritics bool) string {
+	if stripDiacritics {
+		t := transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
+		if stripped, _, err := transform.String(t, s); err == nil {
+			s = stripped
+		}
+	}
+	return cases.Fold().String(s)
+}
@@ -0,0 +1,65 @@
+package main
+
+import "testing"
+
+func TestFoldForMatch(t *testing.T) {
+	tests := []struct {
+		name        string
+		a, b        string
+		equalStrip  bool // gleich mit PROFANITY_STRIP_DIACRITICS (Standard)
+		equalStrict bool // gleich mit PROFANITY_STRIP_DIACRITICS=false
+	}{
+		// Deutsch
+		{"de: upper case", "SHARBERT", "sharbert", true, true},
+		{"de: umlaut case", "ÄRGER", "ärger", true, true},
+		{"de: umlaut vs plain", "Schärbert", "scharbert", true, false},
+		{"de: sharp s", "Straße", "STRASSE", true, true},
+		{"de: capital sharp s", "STRAẞE", "strasse", true, true},
+		// Französisch
+		{"fr: accents", "Élève", "eleve", true, false},
+		{"fr: accents keep case folding", "ÉLÈVE", "élève", true, true},
+		{"fr: cedilla", "Ça", "ca", true, false},
+		{"fr: ligature is a letter", "œuvre", "oeuvre", false, false},
+		// Türkisch: ı und i sind verschiedene Buchstaben
+		{"tr: dotless i", "kız", "kiz", false, false},
+		{"tr: upper I folds to i, not ı", "KIZ", "kız", false, false},
+		{"tr: dotted capital I", "İstanbul", "istanbul", true, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := foldForMatch(tt.a, true) == foldForMatch(tt.b, true); got != tt.equalStrip {
+				t.Errorf("strip diacritics: %q == %q is %v, want %v (%q, %q)", tt.a, tt.b, got, tt.equalStrip,
+					foldForMatch(tt.a, true), foldForMatch(tt.b, true))
+			}
+			if got := foldForMatch(tt.a, false) == foldForMatch(tt.b, false); got != tt.equalStrict {
+				t.Errorf("keep diacritics: %q == %q is %v, want %v (%q, %q)", tt.a, tt.b, got, tt.equalStrict,
+					foldForMatch(tt.a, false), foldForMatch(tt.b, false))
+			}
+		})
+	}
+}
+
+// Dieselbe Normalisierung gilt für die Wortliste und die Tokens im Chirp.
+func TestCleanProfanityFolding(t *testing.T) {
+	tests := []struct {
+		name            string
+		body            string
+		words           []string
+		stripDiacritics bool
+		want            string
+	}{
+		{"de: umlaut in body", "so ein Schärbert", []string{"scharbert"}, true, "so ein ****"},
+		{"de: umlaut kept when strict", "so ein Schärbert", []string{"scharbert"}, false, "so ein Schärbert"},
+		{"de: umlaut in word list", "so ein SCHARBERT", []string{"Schärbert"}, true, "so ein ****"},
+		{"de: sharp s", "GROSSE Straße", []string{"strasse"}, false, "GROSSE ****"},
+		{"fr: accents", "quel FORNÀX", []string{"fornax"}, true, "quel ****"},
+		{"tr: dotless i is not i", "kız kiz", []string{"kiz"}, true, "kız ****"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, _ := cleanProfanity(tt.body, tt.words, tt.stripDiacritics); got != tt.want {
+				t.Errorf("cleanProfanity(%q) = %q, want %q", tt.body, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,159 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"golang.org/x/text/language"
+)
+
+// Obergrenze für die Antwort des Backends; Chirps sind kurz, größere Antworten sind ein Fehler.
+const maxTranslationResponseBytes = 64 << 10
+
+// Standard für TRANSLATE_LANGUAGES
+const defaultTranslateLanguages = "de,en,es,fr,it,nl,pl,pt"
+
+// Wird von Translator-Implementierungen geliefert, wenn kein Backend konfiguriert ist.
+var errTranslationUnavailable = errors.New("translation is not configured")
+
+// translation ist das Ergebnis eines Übersetzungs-Backends.
+type translation struct {
+	Text       string
+	SourceLang string
+}
+
+// Translator übersetzt Text in eine Zielsprache über ein externes Backend.
+type Translator interface {
+	// Name ist der Anbietername, der in Antworten und im Cache gespeichert wird.
+	Name() string
+	Translate(ctx context.Context, text, targetLang string) (translation, error)
+}
+
+// Liest TRANSLATE_URL (und optional TRANSLATE_API_KEY, TRANSLATE_PROVIDER).
+// Ohne URL wird ein Translator geliefert, der immer errTranslationUnavailable meldet.
+func translatorFromEnv() Translator {
+	endpoint := os.Getenv("TRANSLATE_URL")
+	if endpoint == "" {
+		return noopTranslator{}
+	}
+	provider := os.Getenv("TRANSLATE_PROVIDER")
+	if provider == "" {
+		provider = "http"
+	}
+	return &httpTranslator{
+		endpoint: endpoint,
+		apiKey:   os.Getenv("TRANSLATE_API_KEY"),
+		provider: provider,
+		client:   &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Liest die erlaubten Zielsprachen (kommagetrennt, z.B. "de,en"). Jeder Eintrag wird auf
+// seine Basissprache reduziert, damit Regionen und Schriften den Cache nicht vervielfachen.
+func translateLanguagesFromEnv(raw string) ([]string, error) {
+	if raw == "" {
+		raw = defaultTranslateLanguages
+	}
+	var langs []string
+	for _, entry := range strings.Split(raw, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		base, err := translateBaseLanguage(entry)
+		if err != nil {
+			return nil, fmt.Errorf("TRANSLATE_LANGUAGES: %q is not a language tag", entry)
+		}
+		if !slices.Contains(langs, base) {
+			langs = append(langs, base)
+		}
+	}
+	if len(langs) == 0 {
+		return nil, errors.New("TRANSLATE_LANGUAGES must list at least one language")
+	}
+	return langs, nil
+}
+
+// Basissprache eines Tags, z.B. "de" für "de-AT" oder "zh" für "zh-Hant".
+func translateBaseLanguage(tag string) (string, error) {
+	t, err := language.Parse(tag)
+	if err != nil {
+		return "", err
+	}
+	base, confidence := t.Base()
+	if confidence != language.Exact { // "und" würde sonst z.B. zu "en" geraten
+		return "", fmt.Errorf("no base language for %q", tag)
+	}
+	return base.String(), nil
+}
+
+type noopTranslator struct{}
+
+func (noopTranslator) Name() string {
+	return "none"
+}
+
+func (noopTranslator) Translate(ctx context.Context, text, targetLang string) (translation, error) {
+	return translation{}, errTranslationUnavailable
+}
+
+// httpTranslator sendet {"text", "target_lang"} per POST an ein Backend und erwartet
+// {"translated_text", "source_lang"} zurück.
+type httpTranslator struct {
+	endpoint string
+	apiKey   string
+	provider string
+	client   *http.Client
+}
+
+func (t *httpTranslator) Name() string {
+	return t.provider
+}
+
+func (t *httpTranslator) Translate(ctx context.Context, text, targetLang string) (translation, error) {
+	payload, err := json.Marshal(map[string]string{
+		"text":        text,
+		"target_lang": targetLang,
+	})
+	if err != nil {
+		return translation{}, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, t.endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return translation{}, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if t.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+t.apiKey)
+	}
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return translation{}, fmt.Errorf("translation request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return translation{}, fmt.Errorf("translation backend returned %s", resp.Status)
+	}
+
+	var result struct {
+		TranslatedText string `json:"translated_text"`
+		SourceLang     string `json:"source_lang"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxTranslationResponseBytes)).Decode(&result); err != nil {
+		return translation{}, fmt.Errorf("couldn't decode translation response: %w", err)
+	}
+	if result.TranslatedText == "" {
+		return translation{}, errors.New("translation backend returned an empty text")
+	}
+	return translation{Text: result.TranslatedText, SourceLang: result.SourceLang}, nil
+}
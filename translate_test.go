@@ -0,0 +1,117 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestTranslateLanguagesFromEnv(t *testing.T) {
+	tests := []struct {
+		raw     string
+		want    []string
+		wantErr bool
+	}{
+		{"", strings.Split(defaultTranslateLanguages, ","), false},
+		{"de", []string{"de"}, false},
+		{" de-AT, de ,en-GB,zh-Hant ", []string{"de", "en", "zh"}, false},
+		{"und", nil, true},
+		{"de,not a tag", nil, true},
+		{" , ", nil, true},
+	}
+	for _, tt := range tests {
+		got, err := translateLanguagesFromEnv(tt.raw)
+		if (err != nil) != tt.wantErr || !slices.Equal(got, tt.want) {
+			t.Errorf("translateLanguagesFromEnv(%q) = %v, %v; want %v, err %v", tt.raw, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+// countingTranslator übersetzt durch Voranstellen der Zielsprache und zählt die Aufrufe.
+type countingTranslator struct{ calls int }
+
+func (c *countingTranslator) Name() string { return "test" }
+
+func (c *countingTranslator) Translate(ctx context.Context, text, targetLang string) (translation, error) {
+	c.calls++
+	return translation{Text: "[" + targetLang + "] " + text, SourceLang: "en"}, nil
+}
+
+func TestChirpTranslate(t *testing.T) {
+	translator := &countingTranslator{}
+	ts := newTestServer(t, func(cfg *apiConfig) { cfg.translator = translator })
+	_, token := ts.createUser(t, "alice@example.com")
+	chirp := ts.createChirp(t, token, "hello")
+	path := "/api/chirps/" + chirp.ID.String() + "/translate?to="
+
+	// Regionen teilen sich den Cache-Eintrag der Basissprache
+	for _, to := range []string{"de", "de-AT", "DE-ch"} {
+		rec := ts.do(t, "GET", path+to, "", "")
+		expectStatus(t, rec, http.StatusOK)
+		var got struct {
+			TranslatedBody string `json:"translated_body"`
+			Provider       string `json:"provider"`
+		}
+		json.Unmarshal(rec.Body.Bytes(), &got)
+		if got.TranslatedBody != "[de] hello" || got.Provider != "test" {
+			t.Errorf("to=%s: %+v", to, got)
+		}
+	}
+	if translator.calls != 1 {
+		t.Errorf("translator called %d times, want 1 (cached)", translator.calls)
+	}
+
+	for _, to := range []string{"fr", "und", "x-klingon", "", "de;DROP"} {
+		expectStatus(t, ts.do(t, "GET", path+to, "", ""), http.StatusBadRequest)
+	}
+	if translator.calls != 1 {
+		t.Errorf("translator called for a rejected language")
+	}
+}
+
+func TestHTTPTranslator(t *testing.T) {
+	var gotAuth string
+	var gotBody map[string]string
+	reply := `{"translated_text":"hallo","source_lang":"en"}`
+	status := http.StatusOK
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAuth = r.Header.Get("Authorization")
+		json.NewDecoder(r.Body).Decode(&gotBody)
+		w.WriteHeader(status)
+		w.Write([]byte(reply))
+	}))
+	defer srv.Close()
+	tr := &httpTranslator{endpoint: srv.URL, apiKey: "key", provider: "test", client: srv.Client()}
+
+	got, err := tr.Translate(context.Background(), "hello", "de")
+	if err != nil || got != (translation{Text: "hallo", SourceLang: "en"}) {
+		t.Fatalf("Translate = %+v, %v", got, err)
+	}
+	if gotAuth != "Bearer key" || gotBody["text"] != "hello" || gotBody["target_lang"] != "de" {
+		t.Errorf("request: auth %q, body %v", gotAuth, gotBody)
+	}
+
+	tests := []struct {
+		name   string
+		status int
+		reply  string
+	}{
+		{"error status", http.StatusInternalServerError, reply},
+		{"empty text", http.StatusOK, `{"translated_text":""}`},
+		{"not json", http.StatusOK, `<html>`},
+		// Eine zu große Antwort wird nach maxTranslationResponseBytes abgeschnitten und ist dann kein JSON mehr
+		{"oversized response", http.StatusOK, `{"translated_text":"` + strings.Repeat("a", maxTranslationResponseBytes) + `"}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, reply = tt.status, tt.reply
+			if _, err := tr.Translate(context.Background(), "hello", "de"); err == nil {
+				t.Error("Translate succeeded")
+			}
+		})
+	}
+}